@@ -137,7 +137,7 @@ FLAGS="$FLAGS --txpool.blobslots=1000 --txpool.totalblobpoollimit=10000"
 FLAGS="$FLAGS --sync.parallel-state-flushing=false"
 
 if [ "$HIVE_TERMINAL_TOTAL_DIFFICULTY" != "" ]; then
-    JWT_SECRET="0x7365637265747365637265747365637265747365637265747365637265747365"
+    JWT_SECRET="${HIVE_JWTSECRET:-0x7365637265747365637265747365637265747365637265747365637265747365}"
     echo -n $JWT_SECRET > /jwt.secret
     FLAGS="$FLAGS --authrpc.addr=0.0.0.0 --authrpc.jwtsecret=/jwt.secret"
 fi
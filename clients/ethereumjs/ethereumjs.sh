@@ -76,6 +76,9 @@ if [ "$HIVE_CLIQUE_PRIVATEKEY" != "" ]; then
 fi
 
 if [ "$HIVE_TERMINAL_TOTAL_DIFFICULTY" != "" ]; then
+    if [ "$HIVE_JWTSECRET" != "" ]; then
+        echo -n "$HIVE_JWTSECRET" > ./jwtsecret
+    fi
     FLAGS="$FLAGS --jwtSecret ./jwtsecret"
 fi
 
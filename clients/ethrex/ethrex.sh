@@ -99,7 +99,8 @@ FLAGS="$FLAGS --http.addr=0.0.0.0  --authrpc.addr=0.0.0.0"
 
 # We don't support pre merge
 if [ "$HIVE_TERMINAL_TOTAL_DIFFICULTY" != "" ]; then
-    JWT_SECRET="7365637265747365637265747365637265747365637265747365637265747365"
+    JWT_SECRET="${HIVE_JWTSECRET:-7365637265747365637265747365637265747365637265747365637265747365}"
+    JWT_SECRET="${JWT_SECRET#0x}"
     echo -n $JWT_SECRET > /jwt.secret
     FLAGS="$FLAGS  --authrpc.jwtsecret=/jwt.secret"
 else
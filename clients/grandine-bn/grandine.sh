@@ -75,7 +75,7 @@ echo "bootnodes: ${HIVE_ETH2_BOOTNODE_ENRS}"
 CONTAINER_IP=`hostname -i | awk '{print $1;}'`
 
 if [ "$HIVE_ETH2_MERGE_ENABLED" != "" ]; then
-    echo -n "0x7365637265747365637265747365637265747365637265747365637265747365" > /jwtsecret
+    echo -n "${HIVE_JWTSECRET:-0x7365637265747365637265747365637265747365637265747365637265747365}" > /jwtsecret
     merge_option="--eth1-rpc-urls=$HIVE_ETH2_ETH1_ENGINE_RPC_ADDRS --jwt-secret=/jwtsecret"
 fi
 
@@ -69,7 +69,7 @@ echo "bootnodes: ${HIVE_ETH2_BOOTNODE_ENRS}"
 CONTAINER_IP=`hostname -i | awk '{print $1;}'`
 metrics_option=$([[ "$HIVE_ETH2_METRICS_PORT" == "" ]] && echo "" || echo "--metrics --metrics-address=0.0.0.0 --metrics-port=$HIVE_ETH2_METRICS_PORT --metrics-allow-origin=*")
 if [ "$HIVE_ETH2_MERGE_ENABLED" != "" ]; then
-    echo -n "0x7365637265747365637265747365637265747365637265747365637265747365" > /jwtsecret
+    echo -n "${HIVE_JWTSECRET:-0x7365637265747365637265747365637265747365637265747365637265747365}" > /jwtsecret
     merge_option="--execution-endpoints=$HIVE_ETH2_ETH1_ENGINE_RPC_ADDRS --jwt-secrets=/jwtsecret"
 fi
 opt_sync_option=$([[ "$HIVE_ETH2_SAFE_SLOTS_TO_IMPORT_OPTIMISTICALLY" == "" ]] && echo "" || echo "--safe-slots-to-import-optimistically=$HIVE_ETH2_SAFE_SLOTS_TO_IMPORT_OPTIMISTICALLY")
@@ -42,7 +42,7 @@ peer_score_option=$([[ "$HIVE_ETH2_DISABLE_PEER_SCORING" == "" ]] && echo "" ||
 
 echo "bootnodes option : ${bootnodes_option}"
 
-echo -n "0x7365637265747365637265747365637265747365637265747365637265747365" > /jwtsecret
+echo -n "${HIVE_JWTSECRET:-0x7365637265747365637265747365637265747365637265747365637265747365}" > /jwtsecret
 
 echo Starting Lodestar Beacon Node
 
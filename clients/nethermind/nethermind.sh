@@ -49,7 +49,7 @@ set -e
 
 # Generate JWT file if necessary
 if [ "$HIVE_TERMINAL_TOTAL_DIFFICULTY" != "" ]; then
-    JWT_SECRET="0x7365637265747365637265747365637265747365637265747365637265747365"
+    JWT_SECRET="${HIVE_JWTSECRET:-0x7365637265747365637265747365637265747365637265747365637265747365}"
     echo -n $JWT_SECRET > /jwt.secret
 fi
 
@@ -59,7 +59,7 @@ metrics_option=$([[ "$HIVE_ETH2_METRICS_PORT" == "" ]] && echo "" || echo "--met
 builder_option=$([[ "$HIVE_ETH2_BUILDER_ENDPOINT" == "" ]] && echo "" || echo "--payload-builder=true --payload-builder-url=$HIVE_ETH2_BUILDER_ENDPOINT")
 echo BUILDER=$builder_option
 
-echo -n "0x7365637265747365637265747365637265747365637265747365637265747365" > /jwtsecret
+echo -n "${HIVE_JWTSECRET:-0x7365637265747365637265747365637265747365637265747365637265747365}" > /jwtsecret
 
 echo Starting Nimbus Beacon Node
 
@@ -117,7 +117,7 @@ fi
 
 # Configure engine api
 if [ "$HIVE_TERMINAL_TOTAL_DIFFICULTY" != "" ]; then
-  echo "0x7365637265747365637265747365637265747365637265747365637265747365" > /jwtsecret
+  echo "${HIVE_JWTSECRET:-0x7365637265747365637265747365637265747365637265747365637265747365}" > /jwtsecret
   FLAGS="$FLAGS --engine-api:true --engine-api-address:0.0.0.0 --engine-api-port:8551 --jwt-secret:/jwtsecret"
 fi
 
@@ -45,7 +45,7 @@ cat /hive/input/config.yaml
 
 CONTAINER_IP=`hostname -i | awk '{print $1;}'`
 metrics_option=$([[ "$HIVE_ETH2_METRICS_PORT" == "" ]] && echo "--disable-monitoring=true" || echo "--disable-monitoring=false --monitoring-host=0.0.0.0 --monitoring-port=$HIVE_ETH2_METRICS_PORT")
-echo -n "0x7365637265747365637265747365637265747365637265747365637265747365" > /jwtsecret
+echo -n "${HIVE_JWTSECRET:-0x7365637265747365637265747365637265747365637265747365637265747365}" > /jwtsecret
 
 if [[ "$HIVE_ETH2_BOOTNODE_ENRS" == "" ]]; then
     bootnode_option=""
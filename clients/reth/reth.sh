@@ -157,7 +157,8 @@ FLAGS="$FLAGS --http --http.addr=0.0.0.0 --http.api=admin,debug,eth,net,web3"
 FLAGS="$FLAGS --ws --ws.addr=0.0.0.0 --ws.api=admin,debug,eth,net,web3"
 
 if [ "$HIVE_TERMINAL_TOTAL_DIFFICULTY" != "" ]; then
-    JWT_SECRET="7365637265747365637265747365637265747365637265747365637265747365"
+    JWT_SECRET="${HIVE_JWTSECRET:-7365637265747365637265747365637265747365637265747365637265747365}"
+    JWT_SECRET="${JWT_SECRET#0x}"
     echo -n $JWT_SECRET > /jwt.secret
     FLAGS="$FLAGS --authrpc.addr=0.0.0.0 --authrpc.jwtsecret=/jwt.secret"
 fi
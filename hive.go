@@ -19,9 +19,10 @@ import (
 	docker "github.com/fsouza/go-dockerclient"
 )
 
-type buildArgs map[string]string
+// kvFlag is a repeatable flag.Value accepting NAME=VALUE pairs.
+type kvFlag map[string]string
 
-func (args *buildArgs) String() string {
+func (args *kvFlag) String() string {
 	var kv []string
 	for k, v := range *args {
 		kv = append(kv, k+"="+v)
@@ -31,10 +32,10 @@ func (args *buildArgs) String() string {
 }
 
 // Set implements flag.Value.
-func (args *buildArgs) Set(value string) error {
+func (args *kvFlag) Set(value string) error {
 	parts := strings.SplitN(value, "=", 2)
 	if len(parts) != 2 {
-		return errors.New("invalid build argument format, expected ARG=VALUE")
+		return errors.New("invalid format, expected NAME=VALUE")
 	}
 	(*args)[parts[0]] = parts[1]
 	return nil
@@ -63,7 +64,9 @@ Otherwise, it looks for files in the $HOME directory:
 		simRandomSeed         = flag.Int("sim.randomseed", 0, "Randomness seed number (interpreted by simulators).")
 		simTestLimit          = flag.Int("sim.testlimit", 0, "[DEPRECATED] Max `number` of tests to execute per client (interpreted by simulators).")
 		simTimeLimit          = flag.Duration("sim.timelimit", 0, "Simulation `timeout`. Hive aborts the simulator if it exceeds this time.")
+		simTestTimeout        = flag.Duration("sim.testtimeout", 0, "Test case inactivity `timeout`. Hive fails tests that don't report progress within this time.")
 		simLogLevel           = flag.Int("sim.loglevel", 3, "Selects log `level` of client instances. Supports values 0-5.")
+		simCPUSet             = flag.String("sim.cpuset", "", "CPUs in which the simulator container may run, e.g. \"0-3\" or \"1,3\".")
 		simDevMode            = flag.Bool("dev", false, "Only starts the simulator API endpoint (listening at 127.0.0.1:3000 by default) without starting any simulators.")
 		simDevModeAPIEndpoint = flag.String("dev.addr", "127.0.0.1:3000", "Endpoint that the simulator API listens on")
 		useCredHelper         = flag.Bool("docker.cred-helper", false, "(DEPRECATED) Use --docker.auth instead.")
@@ -91,8 +94,11 @@ Otherwise, it looks for files in the $HOME directory:
 	)
 
 	// Add the sim.buildarg flag multiple times to allow multiple build arguments.
-	simBuildArgs := make(buildArgs)
+	simBuildArgs := make(kvFlag)
 	flag.Var(&simBuildArgs, "sim.buildarg", "Argument to pass to the docker engine when building the simulator image, in the form of ARGNAME=VALUE.")
+	// The sim.param flag can also be given multiple times.
+	simParams := make(kvFlag)
+	flag.Var(&simParams, "sim.param", "Parameter for simulators, in the form of NAME=VALUE.")
 
 	// Parse the flags and configure the logger.
 	flag.Parse()
@@ -109,6 +115,9 @@ Otherwise, it looks for files in the $HOME directory:
 	if *simTestLimit > 0 {
 		slog.Warn("Option --sim.testlimit is deprecated and will have no effect.")
 	}
+	if *simCPUSet != "" && !libhive.ValidCPUSet(*simCPUSet) {
+		fatal(fmt.Sprintf("bad --sim.cpuset %q, expected CPU list like \"0-3\" or \"1,3\"", *simCPUSet))
+	}
 
 	// Get the list of simulators.
 	inv, err := libhive.LoadInventory(".")
@@ -202,7 +211,10 @@ Otherwise, it looks for files in the $HOME directory:
 		SimTestPattern:     *simTestPattern,
 		SimParallelism:     *simParallelism,
 		SimRandomSeed:      *simRandomSeed,
+		SimCPUSet:          *simCPUSet,
+		SimParams:          simParams,
 		SimDurationLimit:   *simTimeLimit,
+		TestTimeout:        *simTestTimeout,
 		ClientStartTimeout: *clientTimeout,
 	}
 	runner := libhive.NewRunner(inv, builder, cb)
@@ -1,6 +1,10 @@
 package hivesim
 
-import "slices"
+import (
+	"slices"
+
+	"github.com/ethereum/hive/internal/simapi"
+)
 
 // SuiteID identifies a test suite context.
 type SuiteID uint32
@@ -10,10 +14,14 @@ type TestID uint32
 
 // TestResult describes the outcome of a test.
 type TestResult struct {
-	Pass    bool   `json:"pass"`
-	Details string `json:"details"`
+	Pass         bool          `json:"pass"`
+	Details      string        `json:"details"`
+	Measurements []Measurement `json:"measurements,omitempty"`
 }
 
+// Measurement is a named numeric value reported by a test.
+type Measurement = simapi.Measurement
+
 // TestStartInfo contains metadata about a test which is supplied to the hive API.
 type TestStartInfo struct {
 	Name        string `json:"name"`
@@ -21,6 +29,9 @@ type TestStartInfo struct {
 	Location    string `json:"location"`
 	Category    string `json:"category"`
 	Description string `json:"description"`
+
+	// Parent is the ID of the test which runs this test as a subtest.
+	Parent TestID `json:"parent,omitempty"`
 }
 
 // ExecInfo is the result of running a command in a client container.
@@ -40,6 +51,22 @@ type ClientDefinition struct {
 	Name    string         `json:"name"`
 	Version string         `json:"version"`
 	Meta    ClientMetadata `json:"meta"`
+
+	// Client is the base client name, i.e. the name without nametag. Nametag
+	// distinguishes multiple builds of the same client in a hive run.
+	Client  string `json:"client,omitempty"`
+	Nametag string `json:"nametag,omitempty"`
+
+	// JWTSecret is the engine API JWT secret configured for the client in the
+	// hive client file. If empty, the client uses ENGINEAPI_JWT_SECRET.
+	JWTSecret string `json:"jwtSecret,omitempty"`
+
+	// Params are the default client parameters configured by the hive operator in the
+	// client file. They are not applied automatically. To use them, pass them as an
+	// option when starting the client, before any parameters that should override them:
+	//
+	//	t.StartClient(def.Name, def.Params, Params{"HIVE_NETWORK_ID": "1"})
+	Params Params `json:"params,omitempty"`
 }
 
 // HasRole reports whether the client has the given role.
@@ -90,7 +90,8 @@
 
 	The `T` object can start a client using the `StartClient()` method. `StartClient()` returns an object `Client` with
 	information about the client container. `Client` also offers two methods: `EnodeURL()`, which returns the enode URL
-	of the client, and `RPC()`, which returns an RPC client connected to the client's RPC server.
+	of the client, and `RPC()`, which returns an RPC client connected to the client's RPC server. For other ports,
+	transports or JWT authentication, `RPCDial()` creates a dedicated connection.
 
 	`T` can also run a test against a client using any of the `Run__()` methods. It can also pipe logs and test
 	failures through to the simulation log file, among other methods.
@@ -0,0 +1,159 @@
+// Package engine is a typed client for the Engine API of execution-layer clients.
+//
+// Create a client from the engine API connection of a running hive client:
+//
+//	ec := engine.New(client.EngineAPI())
+//	status, err := ec.NewPayloadV3(ctx, payload, versionedHashes, &beaconRoot)
+package engine
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// These are the Engine API types used by the client.
+type (
+	ExecutableData           = engine.ExecutableData
+	ExecutionPayloadEnvelope = engine.ExecutionPayloadEnvelope
+	PayloadAttributes        = engine.PayloadAttributes
+	PayloadID                = engine.PayloadID
+	PayloadStatusV1          = engine.PayloadStatusV1
+	ForkchoiceStateV1        = engine.ForkchoiceStateV1
+	ForkChoiceResponse       = engine.ForkChoiceResponse
+	ClientVersionV1          = engine.ClientVersionV1
+)
+
+// Payload status values. These are the same as in package beacon/engine of
+// go-ethereum, which declares them as variables.
+const (
+	VALID    = "VALID"
+	INVALID  = "INVALID"
+	SYNCING  = "SYNCING"
+	ACCEPTED = "ACCEPTED"
+)
+
+// Client wraps an RPC connection to the engine API.
+type Client struct {
+	rpc *rpc.Client
+}
+
+// New creates a client. The connection must be authenticated, e.g. using the
+// connection returned by hivesim.Client.EngineAPI.
+func New(c *rpc.Client) *Client {
+	return &Client{rpc: c}
+}
+
+// RPC returns the underlying RPC connection.
+func (c *Client) RPC() *rpc.Client {
+	return c.rpc
+}
+
+// NewPayloadV1 calls engine_newPayloadV1 (Paris).
+func (c *Client) NewPayloadV1(ctx context.Context, payload *ExecutableData) (PayloadStatusV1, error) {
+	var status PayloadStatusV1
+	err := c.rpc.CallContext(ctx, &status, "engine_newPayloadV1", payload)
+	return status, err
+}
+
+// NewPayloadV2 calls engine_newPayloadV2 (Shanghai).
+func (c *Client) NewPayloadV2(ctx context.Context, payload *ExecutableData) (PayloadStatusV1, error) {
+	var status PayloadStatusV1
+	err := c.rpc.CallContext(ctx, &status, "engine_newPayloadV2", payload)
+	return status, err
+}
+
+// NewPayloadV3 calls engine_newPayloadV3 (Cancun).
+func (c *Client) NewPayloadV3(ctx context.Context, payload *ExecutableData, versionedHashes []common.Hash, beaconRoot *common.Hash) (PayloadStatusV1, error) {
+	var status PayloadStatusV1
+	if versionedHashes == nil {
+		versionedHashes = []common.Hash{}
+	}
+	err := c.rpc.CallContext(ctx, &status, "engine_newPayloadV3", payload, versionedHashes, beaconRoot)
+	return status, err
+}
+
+// NewPayloadV4 calls engine_newPayloadV4 (Prague).
+func (c *Client) NewPayloadV4(ctx context.Context, payload *ExecutableData, versionedHashes []common.Hash, beaconRoot *common.Hash, requests [][]byte) (PayloadStatusV1, error) {
+	var status PayloadStatusV1
+	if versionedHashes == nil {
+		versionedHashes = []common.Hash{}
+	}
+	encRequests := make([]hexutil.Bytes, len(requests))
+	for i, r := range requests {
+		encRequests[i] = r
+	}
+	err := c.rpc.CallContext(ctx, &status, "engine_newPayloadV4", payload, versionedHashes, beaconRoot, encRequests)
+	return status, err
+}
+
+// ForkchoiceUpdatedV1 calls engine_forkchoiceUpdatedV1 (Paris).
+func (c *Client) ForkchoiceUpdatedV1(ctx context.Context, state ForkchoiceStateV1, attrs *PayloadAttributes) (ForkChoiceResponse, error) {
+	return c.forkchoiceUpdated(ctx, "engine_forkchoiceUpdatedV1", state, attrs)
+}
+
+// ForkchoiceUpdatedV2 calls engine_forkchoiceUpdatedV2 (Shanghai).
+func (c *Client) ForkchoiceUpdatedV2(ctx context.Context, state ForkchoiceStateV1, attrs *PayloadAttributes) (ForkChoiceResponse, error) {
+	return c.forkchoiceUpdated(ctx, "engine_forkchoiceUpdatedV2", state, attrs)
+}
+
+// ForkchoiceUpdatedV3 calls engine_forkchoiceUpdatedV3 (Cancun and later).
+func (c *Client) ForkchoiceUpdatedV3(ctx context.Context, state ForkchoiceStateV1, attrs *PayloadAttributes) (ForkChoiceResponse, error) {
+	return c.forkchoiceUpdated(ctx, "engine_forkchoiceUpdatedV3", state, attrs)
+}
+
+func (c *Client) forkchoiceUpdated(ctx context.Context, method string, state ForkchoiceStateV1, attrs *PayloadAttributes) (ForkChoiceResponse, error) {
+	var resp ForkChoiceResponse
+	err := c.rpc.CallContext(ctx, &resp, method, state, attrs)
+	return resp, err
+}
+
+// GetPayloadV1 calls engine_getPayloadV1 (Paris).
+func (c *Client) GetPayloadV1(ctx context.Context, id PayloadID) (*ExecutableData, error) {
+	var payload ExecutableData
+	if err := c.rpc.CallContext(ctx, &payload, "engine_getPayloadV1", id); err != nil {
+		return nil, err
+	}
+	return &payload, nil
+}
+
+// GetPayloadV2 calls engine_getPayloadV2 (Shanghai).
+func (c *Client) GetPayloadV2(ctx context.Context, id PayloadID) (*ExecutionPayloadEnvelope, error) {
+	return c.getPayload(ctx, "engine_getPayloadV2", id)
+}
+
+// GetPayloadV3 calls engine_getPayloadV3 (Cancun).
+func (c *Client) GetPayloadV3(ctx context.Context, id PayloadID) (*ExecutionPayloadEnvelope, error) {
+	return c.getPayload(ctx, "engine_getPayloadV3", id)
+}
+
+// GetPayloadV4 calls engine_getPayloadV4 (Prague).
+func (c *Client) GetPayloadV4(ctx context.Context, id PayloadID) (*ExecutionPayloadEnvelope, error) {
+	return c.getPayload(ctx, "engine_getPayloadV4", id)
+}
+
+func (c *Client) getPayload(ctx context.Context, method string, id PayloadID) (*ExecutionPayloadEnvelope, error) {
+	var env ExecutionPayloadEnvelope
+	if err := c.rpc.CallContext(ctx, &env, method, id); err != nil {
+		return nil, err
+	}
+	return &env, nil
+}
+
+// ExchangeCapabilities calls engine_exchangeCapabilities. It returns the
+// engine API methods supported by the client.
+func (c *Client) ExchangeCapabilities(ctx context.Context, methods []string) ([]string, error) {
+	var result []string
+	err := c.rpc.CallContext(ctx, &result, "engine_exchangeCapabilities", methods)
+	return result, err
+}
+
+// GetClientVersionV1 calls engine_getClientVersionV1.
+func (c *Client) GetClientVersionV1(ctx context.Context, info ClientVersionV1) ([]ClientVersionV1, error) {
+	var result []ClientVersionV1
+	err := c.rpc.CallContext(ctx, &result, "engine_getClientVersionV1", info)
+	return result, err
+}
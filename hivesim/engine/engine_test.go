@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"context"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// testEngineAPI records the arguments of engine API calls.
+type testEngineAPI struct {
+	payload         ExecutableData
+	versionedHashes []common.Hash
+	beaconRoot      *common.Hash
+	requests        []hexutil.Bytes
+	fcState         ForkchoiceStateV1
+	fcAttrs         *PayloadAttributes
+}
+
+func (api *testEngineAPI) NewPayloadV4(payload ExecutableData, hashes []common.Hash, root *common.Hash, requests []hexutil.Bytes) PayloadStatusV1 {
+	api.payload, api.versionedHashes, api.beaconRoot, api.requests = payload, hashes, root, requests
+	return PayloadStatusV1{Status: VALID, LatestValidHash: &payload.BlockHash}
+}
+
+func (api *testEngineAPI) ForkchoiceUpdatedV3(state ForkchoiceStateV1, attrs *PayloadAttributes) ForkChoiceResponse {
+	api.fcState, api.fcAttrs = state, attrs
+	id := PayloadID{3, 1, 2, 3, 4, 5, 6, 7}
+	return ForkChoiceResponse{PayloadStatus: PayloadStatusV1{Status: VALID}, PayloadID: &id}
+}
+
+func (api *testEngineAPI) GetPayloadV3(id PayloadID) *ExecutionPayloadEnvelope {
+	return &ExecutionPayloadEnvelope{ExecutionPayload: &api.payload, BlockValue: big.NewInt(1)}
+}
+
+func newTestClient(t *testing.T) (*Client, *testEngineAPI) {
+	api := new(testEngineAPI)
+	srv := rpc.NewServer()
+	if err := srv.RegisterName("engine", api); err != nil {
+		t.Fatal(err)
+	}
+	c := rpc.DialInProc(srv)
+	t.Cleanup(func() {
+		c.Close()
+		srv.Stop()
+	})
+	return New(c), api
+}
+
+func TestNewPayloadV4(t *testing.T) {
+	c, api := newTestClient(t)
+	payload := &ExecutableData{
+		BlockHash:     common.Hash{1},
+		Number:        10,
+		BaseFeePerGas: big.NewInt(7),
+		Transactions:  [][]byte{},
+	}
+	root := common.Hash{2}
+	status, err := c.NewPayloadV4(context.Background(), payload, nil, &root, [][]byte{{0x01, 0xff}})
+	if err != nil {
+		t.Fatal("call failed:", err)
+	}
+	if status.Status != VALID || *status.LatestValidHash != payload.BlockHash {
+		t.Errorf("wrong status: %+v", status)
+	}
+	if api.payload.BlockHash != payload.BlockHash || api.payload.Number != 10 {
+		t.Errorf("wrong payload sent: %+v", api.payload)
+	}
+	if api.versionedHashes == nil || len(api.versionedHashes) != 0 {
+		t.Errorf("versioned hashes should be sent as empty list, got %v", api.versionedHashes)
+	}
+	if *api.beaconRoot != root {
+		t.Errorf("wrong beacon root sent: %v", api.beaconRoot)
+	}
+	if !reflect.DeepEqual(api.requests, []hexutil.Bytes{{0x01, 0xff}}) {
+		t.Errorf("wrong requests sent: %v", api.requests)
+	}
+}
+
+func TestForkchoiceUpdatedAndGetPayload(t *testing.T) {
+	c, api := newTestClient(t)
+	api.payload = ExecutableData{BlockHash: common.Hash{5}, BaseFeePerGas: big.NewInt(7), Transactions: [][]byte{}}
+
+	state := ForkchoiceStateV1{HeadBlockHash: common.Hash{1}, SafeBlockHash: common.Hash{2}}
+	attrs := &PayloadAttributes{Timestamp: 100, BeaconRoot: &common.Hash{3}}
+	resp, err := c.ForkchoiceUpdatedV3(context.Background(), state, attrs)
+	if err != nil {
+		t.Fatal("forkchoiceUpdated failed:", err)
+	}
+	if api.fcState != state || api.fcAttrs == nil || api.fcAttrs.Timestamp != 100 {
+		t.Errorf("wrong forkchoice update sent: %+v %+v", api.fcState, api.fcAttrs)
+	}
+	if resp.PayloadID == nil {
+		t.Fatal("no payload ID returned")
+	}
+
+	env, err := c.GetPayloadV3(context.Background(), *resp.PayloadID)
+	if err != nil {
+		t.Fatal("getPayload failed:", err)
+	}
+	if env.ExecutionPayload.BlockHash != api.payload.BlockHash {
+		t.Errorf("wrong payload returned: %+v", env.ExecutionPayload)
+	}
+}
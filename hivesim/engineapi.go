@@ -1,8 +1,10 @@
 package hivesim
 
 import (
+	"encoding/hex"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/ethereum/go-ethereum/rpc"
@@ -12,6 +14,17 @@ import (
 // This is the static secret configured for all execution-layer clients.
 var ENGINEAPI_JWT_SECRET = [32]byte{0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x65, 0x63, 0x72, 0x65, 0x74, 0x73, 0x65}
 
+// parseJWTSecret decodes a hex-encoded JWT secret.
+func parseJWTSecret(s string) ([32]byte, error) {
+	var secret [32]byte
+	b, err := hex.DecodeString(strings.TrimPrefix(s, "0x"))
+	if err != nil || len(b) != len(secret) {
+		return secret, fmt.Errorf("invalid JWT secret %q", s)
+	}
+	copy(secret[:], b)
+	return secret, nil
+}
+
 func jwtAuth(secret [32]byte) rpc.HTTPAuth {
 	return func(h http.Header) error {
 		token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
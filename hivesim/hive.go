@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"io/fs"
 	"mime/multipart"
 	"net"
 	"net/http"
@@ -15,6 +16,8 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/p2p/enode"
 	"github.com/ethereum/hive/internal/simapi"
@@ -26,6 +29,15 @@ type Simulation struct {
 	m    testMatcher
 	docs *docsCollector
 	ll   int
+
+	// parallel test limit
+	parallelism int
+	poolInit    sync.Once
+	pool        chan struct{}
+
+	// simulator parameters, fetched on first use
+	paramsMu sync.Mutex
+	params   SimParams
 }
 
 // New looks up the hive host URI using the HIVE_SIMULATOR environment variable
@@ -58,6 +70,13 @@ func New() *Simulation {
 	if ll := os.Getenv("HIVE_LOGLEVEL"); ll != "" {
 		sim.ll, _ = strconv.Atoi(ll)
 	}
+	if p := os.Getenv("HIVE_PARALLELISM"); p != "" {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: ignoring invalid HIVE_PARALLELISM value %q\n", p)
+		}
+		sim.parallelism = n
+	}
 	return sim
 }
 
@@ -78,6 +97,27 @@ func (sim *Simulation) SetTestPattern(p string) {
 	sim.m = m
 }
 
+// SetParallelism sets the maximum number of parallel tests (see TestSpec.Parallel) that
+// may run at the same time. For simulator runs launched by hive, the limit is set
+// automatically in New() from the --sim.parallelism flag. This must be called before
+// any tests are run.
+func (sim *Simulation) SetParallelism(n int) {
+	sim.parallelism = n
+}
+
+// acquireTestSlot blocks until a parallel test may run.
+func (sim *Simulation) acquireTestSlot() {
+	sim.poolInit.Do(func() {
+		sim.pool = make(chan struct{}, max(sim.parallelism, 1))
+	})
+	sim.pool <- struct{}{}
+}
+
+// releaseTestSlot is called when a parallel test is done.
+func (sim *Simulation) releaseTestSlot() {
+	<-sim.pool
+}
+
 // TestPattern returns the regular expressions used to enable/skip suite and test names.
 func (sim *Simulation) TestPattern() (suiteExpr string, testNameExpr string) {
 	se := ""
@@ -143,6 +183,86 @@ func (sim *Simulation) StartTest(testSuite SuiteID, test TestStartInfo) (TestID,
 	return resp, err
 }
 
+// TestProgress reports progress of a running test case to the host. If hive runs with a
+// test timeout, this extends the test's deadline. The returned deadline is the time at
+// which the host ends the test unless further progress is reported. It is the zero
+// time if tests have no timeout.
+func (sim *Simulation) TestProgress(testSuite SuiteID, test TestID, message string) (time.Time, error) {
+	if sim.docs != nil {
+		return time.Time{}, errors.New("TestProgress is not supported in docs mode")
+	}
+	var (
+		url  = fmt.Sprintf("%s/testsuite/%d/test/%d/progress", sim.url, testSuite, test)
+		req  = &simapi.TestProgress{Message: message}
+		resp simapi.TestProgressResponse
+	)
+	if err := post(url, req, &resp); err != nil {
+		return time.Time{}, err
+	}
+	if resp.Deadline == nil {
+		return time.Time{}, nil
+	}
+	return *resp.Deadline, nil
+}
+
+// TestDeadline returns the time at which the host will end the given test case, or the
+// zero time if tests have no timeout.
+func (sim *Simulation) TestDeadline(testSuite SuiteID, test TestID) (time.Time, error) {
+	if sim.docs != nil {
+		return time.Time{}, errors.New("TestDeadline is not supported in docs mode")
+	}
+	var (
+		url  = fmt.Sprintf("%s/testsuite/%d/test/%d/progress", sim.url, testSuite, test)
+		resp simapi.TestProgressResponse
+	)
+	if err := get(url, &resp); err != nil {
+		return time.Time{}, err
+	}
+	if resp.Deadline == nil {
+		return time.Time{}, nil
+	}
+	return *resp.Deadline, nil
+}
+
+// UploadTestFiles stores all files in the given directory in the work directory of
+// the test on the host. The host keeps these files as part of the simulation output.
+func (sim *Simulation) UploadTestFiles(testSuite SuiteID, test TestID, dir string) error {
+	if sim.docs != nil {
+		return nil
+	}
+	var files []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			files = append(files, path)
+		}
+		return err
+	})
+	if err != nil || len(files) == 0 {
+		return err
+	}
+
+	url := fmt.Sprintf("%s/testsuite/%d/test/%d/files", sim.url, testSuite, test)
+	return postMultipart(url, nil, func(form *multipart.Writer) error {
+		for _, path := range files {
+			rel, _ := filepath.Rel(dir, path)
+			fw, err := form.CreateFormFile(filepath.ToSlash(rel), filepath.Base(path))
+			if err != nil {
+				return err
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			_, copyErr := io.Copy(fw, f)
+			f.Close()
+			if copyErr != nil {
+				return copyErr
+			}
+		}
+		return nil
+	})
+}
+
 // ClientTypes returns all client types available to this simulator run. This depends on
 // both the available client set and the command line filters.
 func (sim *Simulation) ClientTypes() ([]*ClientDefinition, error) {
@@ -159,6 +279,30 @@ func (sim *Simulation) ClientTypes() ([]*ClientDefinition, error) {
 	return resp, err
 }
 
+// ClientDefinition returns the definition of the named client. If version constraints
+// are given, the client version must satisfy all of them (see ClientDefinition.CheckVersion).
+// This can be used to ensure a test runs against the intended client build.
+//
+// If the client exists but its version can't be checked against the constraints, the
+// definition is returned along with the error, so the version can be reported:
+//
+//	def, err := sim.ClientDefinition("go-ethereum", ">=1.14.0")
+//	if errors.Is(err, hivesim.ErrVersionMismatch) {
+//		// skip test, mentioning def.Version
+//	}
+func (sim *Simulation) ClientDefinition(name string, constraints ...string) (*ClientDefinition, error) {
+	cs, err := sim.ClientTypes()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range cs {
+		if c.Name == name {
+			return c, c.CheckVersion(constraints...)
+		}
+	}
+	return nil, fmt.Errorf("unknown client %q", name)
+}
+
 // ClientsWithRole returns the clients which are tagged with the given role.
 func (sim *Simulation) ClientsWithRole(role string) ([]*ClientDefinition, error) {
 	cs, err := sim.ClientTypes()
@@ -192,8 +336,16 @@ func (sim *Simulation) StartClient(testSuite SuiteID, test TestID, parameters ma
 // StartClientWithOptions starts a new node (or other container) with specified options.
 // Returns container id and ip.
 func (sim *Simulation) StartClientWithOptions(testSuite SuiteID, test TestID, clientType string, options ...StartOption) (string, net.IP, error) {
+	resp, ip, err := sim.startClient(testSuite, test, clientType, options)
+	if resp == nil {
+		return "", nil, err
+	}
+	return resp.ID, ip, err
+}
+
+func (sim *Simulation) startClient(testSuite SuiteID, test TestID, clientType string, options []StartOption) (*simapi.StartNodeResponse, net.IP, error) {
 	if sim.docs != nil {
-		return "", nil, errors.New("StartClientWithOptions is not supported in docs mode")
+		return nil, nil, errors.New("StartClientWithOptions is not supported in docs mode")
 	}
 	var (
 		url  = fmt.Sprintf("%s/testsuite/%d/test/%d/node", sim.url, testSuite, test)
@@ -213,13 +365,13 @@ func (sim *Simulation) StartClientWithOptions(testSuite SuiteID, test TestID, cl
 
 	err := setup.postWithFiles(url, &resp)
 	if err != nil {
-		return "", nil, err
+		return nil, nil, err
 	}
 	ip := net.ParseIP(resp.IP)
 	if ip == nil {
-		return resp.ID, nil, fmt.Errorf("no IP address returned")
+		return &resp, nil, fmt.Errorf("no IP address returned")
 	}
-	return resp.ID, ip, nil
+	return &resp, ip, nil
 }
 
 // StopClient signals to the host that the node is no longer required.
@@ -377,18 +529,44 @@ func (sim *Simulation) ContainerNetworkIP(testSuite SuiteID, network, containerI
 	return resp, err
 }
 
-func (setup *clientSetup) postWithFiles(url string, result interface{}) error {
-	var (
-		pipeR, pipeW = io.Pipe()
-		bufW         = bufio.NewWriter(pipeW)
-		pipeErrCh    = make(chan error, 1)
-		form         = multipart.NewWriter(bufW)
-	)
+// Error codes reported by the simulation API. See APIError.
+const (
+	ErrCodeBadRequest         = simapi.ErrCodeBadRequest
+	ErrCodeNotFound           = simapi.ErrCodeNotFound
+	ErrCodeInternal           = simapi.ErrCodeInternal
+	ErrCodeUnknownClient      = simapi.ErrCodeUnknownClient
+	ErrCodeImageMissing       = simapi.ErrCodeImageMissing
+	ErrCodeClientCreateFailed = simapi.ErrCodeClientCreateFailed
+	ErrCodeClientStartFailed  = simapi.ErrCodeClientStartFailed
+	ErrCodeClientStartTimeout = simapi.ErrCodeClientStartTimeout
+	ErrCodeOutOfDisk          = simapi.ErrCodeOutOfDisk
+)
 
-	go func() (err error) {
-		defer func() { pipeErrCh <- err }()
-		defer pipeW.Close()
+// APIError is returned by Simulation methods when the hive API reports an error.
+// The Code field classifies the error and can be used to decide whether an operation
+// should be retried, skipped or treated as a test failure.
+type APIError struct {
+	StatusCode int    // HTTP status code of the response
+	Code       string // one of the ErrCode... constants, may be empty
+	Message    string
+}
+
+func (e *APIError) Error() string {
+	return e.Message
+}
+
+// ErrorCode returns the API error code of err. It returns the empty string if err is
+// not an APIError.
+func ErrorCode(err error) string {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.Code
+	}
+	return ""
+}
 
+func (setup *clientSetup) postWithFiles(url string, result interface{}) error {
+	return postMultipart(url, result, func(form *multipart.Writer) error {
 		// Write 'config' parameter first.
 		fw, err := form.CreateFormField("config")
 		if err != nil {
@@ -415,7 +593,28 @@ func (setup *clientSetup) postWithFiles(url string, result interface{}) error {
 				return copyErr
 			}
 		}
+		return nil
+	})
+}
 
+// postMultipart sends a multipart/form-data POST request. The form is written by
+// writeForm while the request is being sent.
+func postMultipart(url string, result interface{}, writeForm func(*multipart.Writer) error) error {
+	var (
+		pipeR, pipeW = io.Pipe()
+		bufW         = bufio.NewWriter(pipeW)
+		pipeErrCh    = make(chan error, 1)
+		form         = multipart.NewWriter(bufW)
+	)
+
+	go func() (err error) {
+		defer func() { pipeErrCh <- err }()
+		// Closing the pipe with the error makes the request fail with it.
+		defer func() { pipeW.CloseWithError(err) }()
+
+		if err := writeForm(form); err != nil {
+			return err
+		}
 		// Form must be closed or the request will be missing the terminating boundary.
 		if err := form.Close(); err != nil {
 			return err
@@ -431,7 +630,8 @@ func (setup *clientSetup) postWithFiles(url string, result interface{}) error {
 	req.Header.Set("content-type", form.FormDataContentType())
 	httpErr := request(req, result)
 
-	// Wait for the uploader goroutine to finish.
+	// Wait for the uploader goroutine to finish. If the host rejected the request
+	// before reading the form, writing it fails, so the HTTP error takes precedence.
 	uploadErr := <-pipeErrCh
 	if httpErr == nil && uploadErr != nil {
 		return uploadErr
@@ -494,7 +694,7 @@ func request(httpReq *http.Request, result interface{}) error {
 			if err := dec.Decode(&errobj); err != nil {
 				return fmt.Errorf("request failed (status %d) and can't decode error message: %v", resp.StatusCode, err)
 			}
-			return errors.New(errobj.Error)
+			return &APIError{StatusCode: resp.StatusCode, Code: errobj.Code, Message: errobj.Error}
 		default:
 			respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
 			if len(respBody) == 0 {
@@ -1,7 +1,9 @@
 package hivesim
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"net/http/httptest"
@@ -32,6 +34,7 @@ func TestClientTypes(t *testing.T) {
 			Name:    "client-1",
 			Version: "client-1-version",
 			Meta:    ClientMetadata{Roles: []string{"eth1"}},
+			Params:  Params{"HIVE_CACHE_SIZE": "4096"},
 		},
 		{
 			Name:    "client-2",
@@ -173,6 +176,21 @@ func TestStartClientStartOptions(t *testing.T) {
 		}
 	})
 
+	t.Run("cpuset_option", func(t *testing.T) {
+		_, _, err = sim.StartClientWithOptions(suiteID, testID, "client-1", WithCPUSet("0-1,3"))
+		if err != nil {
+			t.Fatalf("failed to start client: %v", err)
+		}
+		if lastOptions.CPUSet != "0-1,3" {
+			t.Fatalf("wrong cpuset, got: %q", lastOptions.CPUSet)
+		}
+
+		_, _, err = sim.StartClientWithOptions(suiteID, testID, "client-1", WithCPUSet("all"))
+		if ErrorCode(err) != ErrCodeBadRequest {
+			t.Fatalf("wrong error for invalid cpuset: %v", err)
+		}
+	})
+
 	t.Run("files_options", func(t *testing.T) {
 		file1, err := os.CreateTemp("", "hivesim_test")
 		if err != nil {
@@ -240,6 +258,33 @@ func TestStartClientStartOptions(t *testing.T) {
 				t.Fatalf("expected 6 bytes for '/data/bar', got %d", got.Size)
 			}
 		})
+
+		t.Run("template", func(t *testing.T) {
+			data := struct {
+				Name      string
+				JWTSecret [32]byte
+			}{"node-1", ENGINEAPI_JWT_SECRET}
+			_, _, err = sim.StartClientWithOptions(suiteID, testID, "client-1",
+				WithTemplateFile("/config.toml", "name={{.Name}}\njwt={{printf \"%#x\" .JWTSecret}}\n", data))
+			if err != nil {
+				t.Fatalf("failed to start client: %v", err)
+			}
+			got, ok := lastOptions.Files["/config.toml"]
+			if !ok {
+				t.Fatal("missing /config.toml")
+			}
+			// "name=node-1\n" + "jwt=0x<64 hex chars>\n"
+			if want := int64(12 + 71); got.Size != want {
+				t.Fatalf("expected %d bytes for '/config.toml', got %d", want, got.Size)
+			}
+
+			// Invalid templates are reported by StartClient.
+			_, _, err = sim.StartClientWithOptions(suiteID, testID, "client-1",
+				WithTemplateFile("/bad", "{{.Name", data))
+			if err == nil || !strings.Contains(err.Error(), "unclosed action") {
+				t.Fatalf("wrong error for invalid template: %v", err)
+			}
+		})
 	})
 }
 
@@ -338,6 +383,233 @@ func TestStartClientErrors(t *testing.T) {
 	if !strings.Contains(err.Error(), "unknown client type") {
 		t.Fatalf("wrong error for GetNode with unknown client parameter: %q", err.Error())
 	}
+	if code := ErrorCode(err); code != ErrCodeUnknownClient {
+		t.Fatalf("wrong error code for unknown client: %q", code)
+	}
+}
+
+// This test checks that client builds can be selected by nametag.
+func TestStartClientNametag(t *testing.T) {
+	var lastImage string
+	backend := fakes.NewContainerBackend(&fakes.BackendHooks{
+		StartContainer: func(image, containerID string, opt libhive.ContainerOptions) (*libhive.ContainerInfo, error) {
+			lastImage = image
+			return &libhive.ContainerInfo{}, nil
+		},
+	})
+	defs := []*libhive.ClientDefinition{
+		{Name: "go-ethereum_default", Client: "go-ethereum", Nametag: "default", Image: "geth-default"},
+		{Name: "go-ethereum_performance", Client: "go-ethereum", Nametag: "performance", Image: "geth-performance"},
+	}
+	tm := libhive.NewTestManager(libhive.SimEnv{}, backend, defs, libhive.HiveInfo{})
+	srv := httptest.NewServer(tm.API())
+	defer srv.Close()
+	defer tm.Terminate()
+
+	sim := NewAt(srv.URL)
+	suiteID, err := sim.StartSuite(&simapi.TestRequest{Name: "suite"}, "")
+	if err != nil {
+		t.Fatal("can't start suite:", err)
+	}
+	testID, err := sim.StartTest(suiteID, TestStartInfo{Name: "test"})
+	if err != nil {
+		t.Fatal("can't start test:", err)
+	}
+
+	for _, tag := range []string{"performance", "default"} {
+		if _, _, err := sim.StartClientWithOptions(suiteID, testID, "go-ethereum", WithNametag(tag)); err != nil {
+			t.Fatalf("can't start client with nametag %s: %v", tag, err)
+		}
+		if want := "geth-" + tag; lastImage != want {
+			t.Fatalf("wrong image for nametag %s: %q", tag, lastImage)
+		}
+	}
+
+	// The full client name still works.
+	if _, _, err := sim.StartClientWithOptions(suiteID, testID, "go-ethereum_default"); err != nil {
+		t.Fatalf("can't start client by full name: %v", err)
+	}
+
+	_, _, err = sim.StartClientWithOptions(suiteID, testID, "go-ethereum", WithNametag("unknown"))
+	if code := ErrorCode(err); code != ErrCodeUnknownClient {
+		t.Fatalf("wrong error for unknown nametag: %v", err)
+	}
+}
+
+// This test checks that the JWT secret configured in the client definition is passed to
+// the client container, and can be overridden by the simulator.
+func TestStartClientJWTSecret(t *testing.T) {
+	var lastEnv map[string]string
+	backend := fakes.NewContainerBackend(&fakes.BackendHooks{
+		StartContainer: func(image, containerID string, opt libhive.ContainerOptions) (*libhive.ContainerInfo, error) {
+			lastEnv = opt.Env
+			return &libhive.ContainerInfo{}, nil
+		},
+	})
+	const secret = "0x0102030405060708091011121314151617181920212223242526272829303132"
+	defs := []*libhive.ClientDefinition{
+		{Name: "client-1", Image: "client-1", JWTSecret: secret},
+		{Name: "client-2", Image: "client-2"},
+	}
+	tm := libhive.NewTestManager(libhive.SimEnv{}, backend, defs, libhive.HiveInfo{})
+	srv := httptest.NewServer(tm.API())
+	defer srv.Close()
+	defer tm.Terminate()
+
+	sim := NewAt(srv.URL)
+	suiteID, err := sim.StartSuite(&simapi.TestRequest{Name: "suite"}, "")
+	if err != nil {
+		t.Fatal("can't start suite:", err)
+	}
+	testID, err := sim.StartTest(suiteID, TestStartInfo{Name: "test"})
+	if err != nil {
+		t.Fatal("can't start test:", err)
+	}
+
+	tests := []struct {
+		client  string
+		options []StartOption
+		want    string
+	}{
+		{"client-1", nil, secret},
+		{"client-1", []StartOption{Params{"HIVE_JWTSECRET": "0xff"}}, "0xff"},
+		{"client-2", nil, ""},
+	}
+	for _, test := range tests {
+		resp, _, err := sim.startClient(suiteID, testID, test.client, test.options)
+		if err != nil {
+			t.Fatalf("can't start %s: %v", test.client, err)
+		}
+		if lastEnv["HIVE_JWTSECRET"] != test.want {
+			t.Errorf("%s: wrong HIVE_JWTSECRET in container env: %q", test.client, lastEnv["HIVE_JWTSECRET"])
+		}
+		if resp.JWTSecret != test.want {
+			t.Errorf("%s: wrong JWT secret in response: %q", test.client, resp.JWTSecret)
+		}
+	}
+
+	if _, err := parseJWTSecret("0xff"); err == nil {
+		t.Error("expected error for short JWT secret")
+	}
+	s, err := parseJWTSecret(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s[0] != 0x01 || s[31] != 0x32 {
+		t.Errorf("wrong parsed JWT secret %x", s)
+	}
+}
+
+// This test checks that a client is removed if its test ends while it is starting.
+func TestStartClientTestEnded(t *testing.T) {
+	var (
+		tm      *libhive.TestManager
+		suiteID SuiteID
+		testID  TestID
+		deleted []string
+	)
+	tm, srv := newFakeAPI(&fakes.BackendHooks{
+		StartContainer: func(image, containerID string, opt libhive.ContainerOptions) (*libhive.ContainerInfo, error) {
+			tm.EndTest(libhive.TestSuiteID(suiteID), libhive.TestID(testID), &libhive.TestResult{})
+			return &libhive.ContainerInfo{}, nil
+		},
+		DeleteContainer: func(containerID string) error {
+			deleted = append(deleted, containerID)
+			return nil
+		},
+	})
+	defer srv.Close()
+	defer tm.Terminate()
+
+	var err error
+	sim := NewAt(srv.URL)
+	if suiteID, err = sim.StartSuite(&simapi.TestRequest{Name: "suite"}, ""); err != nil {
+		t.Fatal("can't start suite:", err)
+	}
+	if testID, err = sim.StartTest(suiteID, TestStartInfo{Name: "test"}); err != nil {
+		t.Fatal("can't start test:", err)
+	}
+	if _, _, err := sim.StartClientWithOptions(suiteID, testID, "client-1"); err == nil {
+		t.Fatal("expected error starting client in ended test")
+	}
+	if len(deleted) != 1 {
+		t.Fatalf("client container was not removed, deleted: %v", deleted)
+	}
+}
+
+// This test checks that API errors are reported by StartClient when the host rejects
+// the request before reading the uploaded files.
+func TestStartClientEndedTest(t *testing.T) {
+	tm, srv := newFakeAPI(nil)
+	defer srv.Close()
+	defer tm.Terminate()
+
+	sim := NewAt(srv.URL)
+	suiteID, err := sim.StartSuite(&simapi.TestRequest{Name: "suite"}, "")
+	if err != nil {
+		t.Fatal("can't start suite:", err)
+	}
+	testID, err := sim.StartTest(suiteID, TestStartInfo{Name: "test"})
+	if err != nil {
+		t.Fatal("can't start test:", err)
+	}
+	if err := sim.EndTest(suiteID, testID, TestResult{Pass: true}); err != nil {
+		t.Fatal("can't end test:", err)
+	}
+
+	bigFile := WithDynamicFile("/big", func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(make([]byte, 16*1024*1024))), nil
+	})
+	_, _, err = sim.StartClientWithOptions(suiteID, testID, "client-1", bigFile)
+	if code := ErrorCode(err); code != ErrCodeBadRequest {
+		t.Fatalf("wrong error for ended test: %v (code %q)", err, code)
+	}
+}
+
+// This test checks that backend errors are classified by StartClient.
+func TestStartClientErrorCodes(t *testing.T) {
+	var startErr error
+	tm, srv := newFakeAPI(&fakes.BackendHooks{
+		StartContainer: func(image, containerID string, opt libhive.ContainerOptions) (*libhive.ContainerInfo, error) {
+			return nil, startErr
+		},
+	})
+	defer srv.Close()
+	defer tm.Terminate()
+
+	sim := NewAt(srv.URL)
+	suiteID, err := sim.StartSuite(&simapi.TestRequest{Name: "suite"}, "")
+	if err != nil {
+		t.Fatal("can't start suite:", err)
+	}
+	testID, err := sim.StartTest(suiteID, TestStartInfo{Name: "test"})
+	if err != nil {
+		t.Fatal("can't start test:", err)
+	}
+
+	tests := []struct {
+		err  error
+		code string
+	}{
+		{libhive.ErrContainerTimeout, ErrCodeClientStartTimeout},
+		{fmt.Errorf("%w: client-1", libhive.ErrImageNotFound), ErrCodeImageMissing},
+		{errors.New("write /data: no space left on device"), ErrCodeOutOfDisk},
+		{errors.New("terminated unexpectedly"), ErrCodeClientStartFailed},
+	}
+	for _, test := range tests {
+		startErr = test.err
+		_, _, err := sim.StartClientWithOptions(suiteID, testID, "client-1")
+		if err == nil {
+			t.Fatalf("no error for backend error %q", test.err)
+		}
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("error is not an APIError: %T", err)
+		}
+		if apiErr.Code != test.code {
+			t.Errorf("wrong code for backend error %q: got %q, want %q", test.err, apiErr.Code, test.code)
+		}
+	}
 }
 
 func TestStartClientInitialNetworks(t *testing.T) {
@@ -401,11 +673,14 @@ func TestStartClientInitialNetworks(t *testing.T) {
 }
 
 func newFakeAPI(hooks *fakes.BackendHooks) (*libhive.TestManager, *httptest.Server) {
+	return newFakeAPIWithEnv(hooks, libhive.SimEnv{})
+}
+
+func newFakeAPIWithEnv(hooks *fakes.BackendHooks, env libhive.SimEnv) (*libhive.TestManager, *httptest.Server) {
 	defs := []*libhive.ClientDefinition{
-		{Name: "client-1", Image: "/ignored/in/api", Version: "client-1-version", Meta: libhive.ClientMetadata{Roles: []string{"eth1"}}},
+		{Name: "client-1", Image: "/ignored/in/api", Version: "client-1-version", Meta: libhive.ClientMetadata{Roles: []string{"eth1"}}, Params: map[string]string{"HIVE_CACHE_SIZE": "4096"}},
 		{Name: "client-2", Image: "/not/exposed/", Version: "client-2-version", Meta: libhive.ClientMetadata{Roles: []string{"beacon"}}},
 	}
-	env := libhive.SimEnv{}
 	backend := fakes.NewContainerBackend(hooks)
 	hiveInfo := libhive.HiveInfo{
 		Command: []string{"/hive"},
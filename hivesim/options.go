@@ -1,8 +1,10 @@
 package hivesim
 
 import (
+	"bytes"
 	"io"
 	"os"
+	"text/template"
 
 	"github.com/ethereum/hive/internal/simapi"
 )
@@ -37,6 +39,22 @@ func WithInitialNetworks(networks []string) StartOption {
 	})
 }
 
+// WithNametag selects a build of the client by nametag. When this option is used, the
+// client type passed to StartClient is the base client name, e.g. "go-ethereum".
+func WithNametag(nametag string) StartOption {
+	return optionFunc(func(setup *clientSetup) {
+		setup.config.Nametag = nametag
+	})
+}
+
+// WithCPUSet restricts the client container to the given CPUs. The argument uses the
+// format of docker's --cpuset-cpus flag, e.g. "0-3" or "1,3".
+func WithCPUSet(cpus string) StartOption {
+	return optionFunc(func(setup *clientSetup) {
+		setup.config.CPUSet = cpus
+	})
+}
+
 // WithStaticFiles adds files from the local filesystem to the client. Map: destination file path -> source file path.
 func WithStaticFiles(initFiles map[string]string) StartOption {
 	return optionFunc(func(setup *clientSetup) {
@@ -57,6 +75,29 @@ func WithDynamicFile(dstPath string, src func() (io.ReadCloser, error)) StartOpt
 	})
 }
 
+// WithTemplateFile adds a file to a client, rendered from the given text/template source
+// each time the client is started. The template is executed with data as dot, so it
+// can refer to values which are only known at start time, for example another client:
+//
+//	hivesim.WithTemplateFile("/config.toml", `Bootnodes = ["{{.EnodeURL}}"]`, bootnode)
+//
+// The engine API JWT secret depends on the client, so it must be passed in data as well,
+// e.g. as the result of Client.JWTSecret, and can be rendered using {{printf "%#x" .JWTSecret}}.
+// Errors in the template are reported when the client is started.
+func WithTemplateFile(dstPath string, text string, data any) StartOption {
+	tmpl, parseErr := template.New(dstPath).Parse(text)
+	return WithDynamicFile(dstPath, func() (io.ReadCloser, error) {
+		if parseErr != nil {
+			return nil, parseErr
+		}
+		buf := new(bytes.Buffer)
+		if err := tmpl.Execute(buf, data); err != nil {
+			return nil, err
+		}
+		return io.NopCloser(buf), nil
+	})
+}
+
 // Bundle combines start options, e.g. to bundle files together as option.
 func Bundle(option ...StartOption) StartOption {
 	return optionFunc(func(setup *clientSetup) {
@@ -0,0 +1,70 @@
+package hivesim
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// RPCOptions configures connections created by Client.RPCDial.
+type RPCOptions struct {
+	// WebSocket selects the WebSocket transport. The default is HTTP.
+	WebSocket bool
+
+	// JWTSecret enables JWT authentication using the given secret.
+	// To connect to the engine API, use the secret returned by Client.JWTSecret.
+	JWTSecret *[32]byte
+}
+
+// RPCClient is an RPC connection to a client. In addition to the methods of rpc.Client,
+// it provides helpers for common calls.
+type RPCClient struct {
+	*rpc.Client
+}
+
+// RPCDial creates a new RPC connection to the given port of the client.
+// Unlike the connections returned by RPC and EngineAPI, the returned client is not
+// shared, and must be closed by the caller.
+func (c *Client) RPCDial(port int, opts RPCOptions) (*RPCClient, error) {
+	scheme := "http"
+	if opts.WebSocket {
+		scheme = "ws"
+	}
+	url := fmt.Sprintf("%s://%v:%d", scheme, c.IP, port)
+
+	var dialOpts []rpc.ClientOption
+	if opts.JWTSecret != nil {
+		dialOpts = append(dialOpts, rpc.WithHTTPAuth(jwtAuth(*opts.JWTSecret)))
+	}
+	rc, err := rpc.DialOptions(context.Background(), url, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &RPCClient{rc}, nil
+}
+
+// BlockNumber returns the number of the client's latest block (eth_blockNumber).
+func (c *RPCClient) BlockNumber(ctx context.Context) (uint64, error) {
+	var n hexutil.Uint64
+	err := c.CallContext(ctx, &n, "eth_blockNumber")
+	return uint64(n), err
+}
+
+// ChainID returns the chain ID of the client (eth_chainId).
+func (c *RPCClient) ChainID(ctx context.Context) (*big.Int, error) {
+	var id hexutil.Big
+	if err := c.CallContext(ctx, &id, "eth_chainId"); err != nil {
+		return nil, err
+	}
+	return (*big.Int)(&id), nil
+}
+
+// ClientVersion returns the version string of the client (web3_clientVersion).
+func (c *RPCClient) ClientVersion(ctx context.Context) (string, error) {
+	var v string
+	err := c.CallContext(ctx, &v, "web3_clientVersion")
+	return v, err
+}
@@ -0,0 +1,68 @@
+package hivesim
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type testEthService struct{}
+
+func (testEthService) BlockNumber() hexutil.Uint64 { return 42 }
+func (testEthService) ChainId() *hexutil.Big       { return (*hexutil.Big)(big.NewInt(1337)) }
+
+type testWeb3Service struct{}
+
+func (testWeb3Service) ClientVersion() string { return "Geth/v1.16.4" }
+
+// This test checks the RPC helpers of RPCClient, and that RPCDial sets up JWT
+// authentication for both transports.
+func TestRPCDial(t *testing.T) {
+	srv := rpc.NewServer()
+	defer srv.Stop()
+	srv.RegisterName("eth", testEthService{})
+	srv.RegisterName("web3", testWeb3Service{})
+
+	var authHeader string
+	httpsrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		if r.Header.Get("Upgrade") == "websocket" {
+			srv.WebsocketHandler([]string{"*"}).ServeHTTP(w, r)
+		} else {
+			srv.ServeHTTP(w, r)
+		}
+	}))
+	defer httpsrv.Close()
+
+	addr := httpsrv.Listener.Addr().(*net.TCPAddr)
+	client := &Client{IP: addr.IP}
+
+	for _, ws := range []bool{false, true} {
+		authHeader = ""
+		rc, err := client.RPCDial(addr.Port, RPCOptions{WebSocket: ws, JWTSecret: &ENGINEAPI_JWT_SECRET})
+		if err != nil {
+			t.Fatal("dial error:", err)
+		}
+		ctx := context.Background()
+		if n, err := rc.BlockNumber(ctx); err != nil || n != 42 {
+			t.Errorf("ws=%t: wrong block number %d, err=%v", ws, n, err)
+		}
+		if id, err := rc.ChainID(ctx); err != nil || id.Int64() != 1337 {
+			t.Errorf("ws=%t: wrong chain ID %v, err=%v", ws, id, err)
+		}
+		if v, err := rc.ClientVersion(ctx); err != nil || v != "Geth/v1.16.4" {
+			t.Errorf("ws=%t: wrong client version %q, err=%v", ws, v, err)
+		}
+		if !strings.HasPrefix(authHeader, "Bearer ") {
+			t.Errorf("ws=%t: missing JWT auth header, got %q", ws, authHeader)
+		}
+		rc.Close()
+	}
+}
@@ -0,0 +1,77 @@
+package hivesim
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// SimParams are the simulator parameters, given to hive using the --sim.param flag.
+//
+// The typed getters return the default value if the parameter is not set. If the
+// parameter value is invalid, a warning is printed and the default is returned.
+type SimParams map[string]string
+
+// String returns the value of the parameter key.
+func (p SimParams) String(key string, def string) string {
+	if v, ok := p[key]; ok {
+		return v
+	}
+	return def
+}
+
+// Int returns the value of the parameter key as an integer.
+func (p SimParams) Int(key string, def int) int {
+	return getParam(p, key, def, strconv.Atoi)
+}
+
+// Bool returns the value of the parameter key as a boolean.
+func (p SimParams) Bool(key string, def bool) bool {
+	return getParam(p, key, def, strconv.ParseBool)
+}
+
+// Duration returns the value of the parameter key as a duration, e.g. "1m30s".
+func (p SimParams) Duration(key string, def time.Duration) time.Duration {
+	return getParam(p, key, def, time.ParseDuration)
+}
+
+func getParam[T any](p SimParams, key string, def T, parse func(string) (T, error)) T {
+	v, ok := p[key]
+	if !ok {
+		return def
+	}
+	x, err := parse(v)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring invalid value %q of simulator parameter %s: %v\n", v, key, err)
+		return def
+	}
+	return x
+}
+
+// Params returns the simulator parameters. They are fetched from the host on first use.
+// If the parameters can't be fetched, a warning is printed and no parameters are returned.
+func (sim *Simulation) Params() SimParams {
+	if sim.docs != nil {
+		return SimParams{}
+	}
+	sim.paramsMu.Lock()
+	defer sim.paramsMu.Unlock()
+	if sim.params == nil {
+		var params SimParams
+		if err := get(fmt.Sprintf("%s/parameters", sim.url), &params); err != nil {
+			fmt.Fprintln(os.Stderr, "Warning: can't get simulator parameters:", err)
+			return SimParams{}
+		}
+		if params == nil {
+			params = SimParams{}
+		}
+		sim.params = params
+	}
+	return sim.params
+}
+
+// Param returns the value of a simulator parameter, or the empty string if it is not set.
+func (sim *Simulation) Param(key string) string {
+	return sim.Params()[key]
+}
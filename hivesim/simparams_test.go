@@ -0,0 +1,62 @@
+package hivesim
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/hive/internal/libhive"
+)
+
+// This test checks that simulator parameters are served by the API.
+func TestSimParams(t *testing.T) {
+	env := libhive.SimEnv{
+		SimParams: map[string]string{
+			"blocks":  "100",
+			"verbose": "true",
+			"timeout": "1m30s",
+			"name":    "mainnet",
+			"bad":     "x",
+		},
+	}
+	tm, srv := newFakeAPIWithEnv(nil, env)
+	defer srv.Close()
+	defer tm.Terminate()
+
+	sim := NewAt(srv.URL)
+	if v := sim.Param("name"); v != "mainnet" {
+		t.Errorf("wrong value for name: %q", v)
+	}
+	params := sim.Params()
+	if v := params.Int("blocks", 1); v != 100 {
+		t.Errorf("wrong value for blocks: %d", v)
+	}
+	if v := params.Bool("verbose", false); !v {
+		t.Errorf("wrong value for verbose: %t", v)
+	}
+	if v := params.Duration("timeout", time.Second); v != 90*time.Second {
+		t.Errorf("wrong value for timeout: %v", v)
+	}
+
+	// Defaults are returned for missing and invalid values.
+	if v := params.String("missing", "def"); v != "def" {
+		t.Errorf("wrong value for missing string: %q", v)
+	}
+	if v := params.Int("missing", 7); v != 7 {
+		t.Errorf("wrong value for missing int: %d", v)
+	}
+	if v := params.Int("bad", 7); v != 7 {
+		t.Errorf("wrong value for invalid int: %d", v)
+	}
+}
+
+// This test checks that Params works when no parameters are set.
+func TestSimParamsEmpty(t *testing.T) {
+	tm, srv := newFakeAPI(nil)
+	defer srv.Close()
+	defer tm.Terminate()
+
+	sim := NewAt(srv.URL)
+	if params := sim.Params(); params == nil || len(params) != 0 {
+		t.Fatalf("wrong params: %v", params)
+	}
+}
@@ -2,12 +2,15 @@ package hivesim
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net"
 	"os"
 	"runtime"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/hive/internal/simapi"
@@ -41,7 +44,7 @@ func (s *Suite) Add(test AnyTest) *Suite {
 
 // AnyTest is a TestSpec or ClientTestSpec.
 type AnyTest interface {
-	runTest(*Simulation, SuiteID, *Suite) error
+	runTest(host *Simulation, suiteID SuiteID, suite *Suite, parent TestID) error
 }
 
 // Run executes all given test suites.
@@ -77,12 +80,34 @@ func RunSuite(host *Simulation, suite Suite) error {
 	}
 	defer host.EndSuite(suiteID)
 
+	var (
+		wg     sync.WaitGroup
+		errMu  sync.Mutex
+		runErr error
+	)
+	defer wg.Wait()
 	for _, test := range suite.Tests {
-		if err := test.runTest(host, suiteID, &suite); err != nil {
+		if spec, ok := test.(TestSpec); ok && spec.Parallel {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := spec.runTest(host, suiteID, &suite, 0); err != nil {
+					errMu.Lock()
+					runErr = errors.Join(runErr, err)
+					errMu.Unlock()
+				}
+			}()
+			continue
+		}
+		// Sequential tests don't take a slot, so wait for pending parallel tests
+		// to stay within the parallelism limit.
+		wg.Wait()
+		if err := test.runTest(host, suiteID, &suite, 0); err != nil {
 			return err
 		}
 	}
-	return nil
+	wg.Wait()
+	return runErr
 }
 
 // MustRunSuite runs the given suite, exiting the process if there is a problem reaching
@@ -118,6 +143,14 @@ type TestSpec struct {
 	// then perform further tests against it.
 	AlwaysRun bool
 
+	// If Parallel is true, the test runs concurrently with other parallel tests, and
+	// T.Run returns without waiting for it. The parent test (or suite) ends only after
+	// all of its parallel subtests are done. The number of parallel tests running at
+	// the same time is limited by the simulation parallelism, see SetParallelism.
+	// A suite test which is not parallel runs after all preceding parallel tests of the
+	// suite have ended.
+	Parallel bool
+
 	// The Run function is invoked when the test executes.
 	Run func(*T)
 }
@@ -163,6 +196,7 @@ type Client struct {
 	mu        sync.Mutex
 	rpc       *rpc.Client
 	enginerpc *rpc.Client
+	jwtSecret [32]byte
 	test      *T
 }
 
@@ -186,6 +220,12 @@ func (c *Client) RPC() *rpc.Client {
 	return c.rpc
 }
 
+// JWTSecret returns the engine API JWT secret of the client. This is the secret
+// configured for the client in the hive client file, or ENGINEAPI_JWT_SECRET by default.
+func (c *Client) JWTSecret() [32]byte {
+	return c.jwtSecret
+}
+
 // EngineAPI returns an RPC client connected to an execution-layer client's engine API server.
 func (c *Client) EngineAPI() *rpc.Client {
 	c.mu.Lock()
@@ -194,7 +234,7 @@ func (c *Client) EngineAPI() *rpc.Client {
 	if c.enginerpc != nil {
 		return c.enginerpc
 	}
-	auth := rpc.WithHTTPAuth(jwtAuth(ENGINEAPI_JWT_SECRET))
+	auth := rpc.WithHTTPAuth(jwtAuth(c.jwtSecret))
 	url := fmt.Sprintf("http://%v:8551", c.IP)
 	c.enginerpc, _ = rpc.DialOptions(context.Background(), url, auth)
 	return c.enginerpc
@@ -227,15 +267,27 @@ type T struct {
 	suite   *Suite
 	mu      sync.Mutex
 	result  TestResult
+
+	// parallel subtests
+	subtests sync.WaitGroup
+
+	// local work directory, created by WorkDir
+	workDir string
 }
 
 // StartClient starts a client instance. If the client cannot by started, the test fails immediately.
 func (t *T) StartClient(clientType string, option ...StartOption) *Client {
-	container, ip, err := t.Sim.StartClientWithOptions(t.SuiteID, t.TestID, clientType, option...)
+	resp, ip, err := t.Sim.startClient(t.SuiteID, t.TestID, clientType, option)
 	if err != nil {
 		t.Fatalf("can't launch node (type %s): %v", clientType, err)
 	}
-	return &Client{Type: clientType, Container: container, IP: ip, test: t}
+	secret := ENGINEAPI_JWT_SECRET
+	if resp.JWTSecret != "" {
+		if secret, err = parseJWTSecret(resp.JWTSecret); err != nil {
+			t.Fatalf("can't launch node (type %s): %v", clientType, err)
+		}
+	}
+	return &Client{Type: clientType, Container: resp.ID, IP: ip, jwtSecret: secret, test: t}
 }
 
 // RunClient runs the given client test against a single client type.
@@ -244,6 +296,7 @@ func (t *T) RunClient(clientType string, spec ClientTestSpec) {
 	test := testSpec{
 		suiteID:     t.SuiteID,
 		suite:       t.suite,
+		parent:      t.TestID,
 		name:        clientTestName(spec.Name, clientType),
 		displayName: spec.DisplayName,
 		category:    spec.Category,
@@ -259,14 +312,23 @@ func (t *T) RunClient(clientType string, spec ClientTestSpec) {
 // RunAllClients runs the given client test against all available client types.
 // It waits for all subtests to complete.
 func (t *T) RunAllClients(spec ClientTestSpec) {
-	spec.runTest(t.Sim, t.SuiteID, t.suite)
+	spec.runTest(t.Sim, t.SuiteID, t.suite, t.TestID)
 }
 
-// Run runs a subtest of this test. It waits for the subtest to complete before continuing.
-// It is safe to call this from multiple goroutines concurrently, just be sure to wait for
-// all your tests to finish until returning from the parent test.
+// Run runs a subtest of this test. It waits for the subtest to complete before continuing,
+// unless spec.Parallel is set. It is safe to call this from multiple goroutines
+// concurrently, just be sure to wait for all your tests to finish until returning from
+// the parent test.
 func (t *T) Run(spec TestSpec) {
-	spec.runTest(t.Sim, t.SuiteID, t.suite)
+	if spec.Parallel {
+		t.subtests.Add(1)
+		go func() {
+			defer t.subtests.Done()
+			spec.runTest(t.Sim, t.SuiteID, t.suite, t.TestID)
+		}()
+		return
+	}
+	spec.runTest(t.Sim, t.SuiteID, t.suite, t.TestID)
 }
 
 // Error is like testing.T.Error.
@@ -312,6 +374,75 @@ func (t *T) Log(values ...interface{}) {
 	t.result.Details += fmt.Sprintln(values...)
 }
 
+// Measure records a named measurement in the test result. Unlike log output,
+// measurements are stored as structured data and can be compared across runs.
+//
+// NaN and infinite values can't be stored. They are ignored with a warning in the test log.
+func (t *T) Measure(name string, value float64, unit string) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		t.Logf("Warning: ignoring invalid measurement %s = %v", name, value)
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.result.Measurements = append(t.result.Measurements, Measurement{Name: name, Value: value, Unit: unit})
+}
+
+// Progress reports that the test is still making progress. The message is printed to
+// standard output, which goes to the simulation log file. When hive runs with a test
+// timeout (--sim.testtimeout), long-running tests must call Progress periodically to
+// avoid being ended by the host.
+func (t *T) Progress(msg string) {
+	fmt.Println(msg)
+	if _, err := t.Sim.TestProgress(t.SuiteID, t.TestID, msg); err != nil {
+		fmt.Fprintln(os.Stderr, "Warning: can't report test progress:", err)
+	}
+}
+
+// Deadline reports the time at which the test will be ended by the host, unless further
+// progress is reported. The ok result is false if the test has no timeout.
+func (t *T) Deadline() (deadline time.Time, ok bool) {
+	deadline, err := t.Sim.TestDeadline(t.SuiteID, t.TestID)
+	if err != nil || deadline.IsZero() {
+		return time.Time{}, false
+	}
+	return deadline, true
+}
+
+// WorkDir returns a scratch directory for the test, creating it on first use. When the
+// test ends, all files in the directory are stored in the simulation output on the host,
+// and the local directory is removed.
+//
+// If the directory cannot be created, the test fails immediately.
+func (t *T) WorkDir() string {
+	var err error
+	t.mu.Lock()
+	if t.workDir == "" {
+		t.workDir, err = os.MkdirTemp("", "hivesim-test-")
+	}
+	dir := t.workDir
+	t.mu.Unlock()
+
+	if err != nil {
+		t.Fatalf("can't create work directory: %v", err)
+	}
+	return dir
+}
+
+// storeWorkDir uploads the work directory to the host and removes it.
+func (t *T) storeWorkDir() {
+	t.mu.Lock()
+	dir := t.workDir
+	t.mu.Unlock()
+	if dir == "" {
+		return
+	}
+	if err := t.Sim.UploadTestFiles(t.SuiteID, t.TestID, dir); err != nil {
+		t.Logf("Warning: can't store work directory: %v", err)
+	}
+	os.RemoveAll(dir)
+}
+
 // Failed reports whether the test has already failed.
 func (t *T) Failed() bool {
 	t.mu.Lock()
@@ -336,11 +467,13 @@ func (t *T) FailNow() {
 type testSpec struct {
 	suiteID     SuiteID
 	suite       *Suite
+	parent      TestID
 	name        string
 	displayName string
 	category    string
 	desc        string
 	alwaysRun   bool
+	parallel    bool
 }
 
 func (spec testSpec) request() TestStartInfo {
@@ -349,6 +482,7 @@ func (spec testSpec) request() TestStartInfo {
 		DisplayName: spec.displayName,
 		Category:    spec.category,
 		Description: spec.desc,
+		Parent:      spec.parent,
 	}
 }
 
@@ -360,6 +494,10 @@ func runTest(host *Simulation, test testSpec, runit func(t *T)) error {
 		return nil
 	}
 
+	if test.parallel {
+		host.acquireTestSlot()
+	}
+
 	// Register test on simulation server and initialize the T.
 	t := &T{
 		Sim:     host,
@@ -368,11 +506,15 @@ func runTest(host *Simulation, test testSpec, runit func(t *T)) error {
 	}
 	testID, err := host.StartTest(test.suiteID, test.request())
 	if err != nil {
+		if test.parallel {
+			host.releaseTestSlot()
+		}
 		return err
 	}
 	t.TestID = testID
 	t.result.Pass = true
 	defer func() {
+		t.storeWorkDir()
 		t.mu.Lock()
 		defer t.mu.Unlock()
 		host.EndTest(test.suiteID, testID, t.result)
@@ -397,10 +539,17 @@ func runTest(host *Simulation, test testSpec, runit func(t *T)) error {
 		runit(t)
 	}()
 	<-done
+
+	// Parallel subtests may be waiting for a slot, so release ours before waiting
+	// for them to finish.
+	if test.parallel {
+		host.releaseTestSlot()
+	}
+	t.subtests.Wait()
 	return nil
 }
 
-func (spec ClientTestSpec) runTest(host *Simulation, suiteID SuiteID, suite *Suite) error {
+func (spec ClientTestSpec) runTest(host *Simulation, suiteID SuiteID, suite *Suite, parent TestID) error {
 	clients, err := host.ClientTypes()
 	if err != nil {
 		return err
@@ -414,6 +563,7 @@ func (spec ClientTestSpec) runTest(host *Simulation, suiteID SuiteID, suite *Sui
 		test := testSpec{
 			suiteID:     suiteID,
 			suite:       suite,
+			parent:      parent,
 			name:        clientTestName(spec.Name, clientDef.Name),
 			displayName: spec.DisplayName,
 			category:    spec.Category,
@@ -442,15 +592,17 @@ func clientTestName(name, clientType string) string {
 	return name + " (" + clientType + ")"
 }
 
-func (spec TestSpec) runTest(host *Simulation, suiteID SuiteID, suite *Suite) error {
+func (spec TestSpec) runTest(host *Simulation, suiteID SuiteID, suite *Suite, parent TestID) error {
 	test := testSpec{
 		suiteID:     suiteID,
 		suite:       suite,
+		parent:      parent,
 		name:        spec.Name,
 		displayName: spec.DisplayName,
 		category:    spec.Category,
 		desc:        spec.Description,
 		alwaysRun:   spec.AlwaysRun,
+		parallel:    spec.Parallel,
 	}
 	return runTest(host, test, spec.Run)
 }
@@ -1,12 +1,18 @@
 package hivesim
 
 import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/ethereum/hive/internal/fakes"
 	"github.com/ethereum/hive/internal/libhive"
 )
 
@@ -74,6 +80,336 @@ func TestSuiteReporting(t *testing.T) {
 	}
 }
 
+// This test checks that measurements are reported in the test result.
+func TestMeasurements(t *testing.T) {
+	suite := Suite{Name: "measurements"}
+	suite.Add(TestSpec{
+		Name: "benchmark",
+		Run: func(t *T) {
+			t.Measure("throughput", 412.5, "MGas/s")
+			t.Measure("blocks", 100, "")
+			// Non-finite values are not stored.
+			t.Measure("nan", math.NaN(), "")
+			t.Measure("inf", math.Inf(1), "MGas/s")
+		},
+	})
+
+	tm, srv := newFakeAPI(nil)
+	defer srv.Close()
+
+	if err := RunSuite(NewAt(srv.URL), suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	results := tm.Results()
+	got := results[0].TestCases[1].SummaryResult.Measurements
+	want := []Measurement{
+		{Name: "throughput", Value: 412.5, Unit: "MGas/s"},
+		{Name: "blocks", Value: 100},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatal("wrong measurements reported:", spew.Sdump(got))
+	}
+	if r := results[0].TestCases[1].SummaryResult; !r.Pass {
+		t.Fatal("test failed:", spew.Sdump(r))
+	}
+}
+
+// This test checks that the host ends tests which don't report progress.
+func TestTestTimeout(t *testing.T) {
+	suite := Suite{Name: "timeout"}
+	suite.Add(TestSpec{
+		Name: "progress",
+		Run: func(t *T) {
+			for i := 0; i < 8; i++ {
+				time.Sleep(100 * time.Millisecond)
+				t.Progress("still running")
+			}
+			if _, ok := t.Deadline(); !ok {
+				t.Fatal("test has no deadline")
+			}
+		},
+	})
+	suite.Add(TestSpec{
+		Name: "stuck",
+		Run: func(t *T) {
+			time.Sleep(time.Second)
+		},
+	})
+
+	tm, srv := newFakeAPIWithEnv(nil, libhive.SimEnv{TestTimeout: 500 * time.Millisecond})
+	defer srv.Close()
+
+	if err := RunSuite(NewAt(srv.URL), suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	results := tm.Results()[0].TestCases
+	if r := results[1].SummaryResult; !r.Pass || r.Timeout {
+		t.Errorf("test reporting progress was ended: %s", spew.Sdump(r))
+	}
+	if r := results[2].SummaryResult; r.Pass || !r.Timeout {
+		t.Errorf("stuck test was not ended by timeout: %s", spew.Sdump(r))
+	}
+}
+
+// This test checks that the test timeout does not fire while a client is starting.
+func TestTestTimeoutClientStart(t *testing.T) {
+	suite := Suite{Name: "timeout"}
+	suite.Add(TestSpec{
+		Name: "slow client",
+		Run: func(t *T) {
+			t.StartClient("client-1")
+		},
+	})
+
+	tm, srv := newFakeAPIWithEnv(&fakes.BackendHooks{
+		StartContainer: func(image, containerID string, opt libhive.ContainerOptions) (*libhive.ContainerInfo, error) {
+			time.Sleep(800 * time.Millisecond)
+			return &libhive.ContainerInfo{}, nil
+		},
+	}, libhive.SimEnv{TestTimeout: 300 * time.Millisecond})
+	defer srv.Close()
+
+	if err := RunSuite(NewAt(srv.URL), suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	if r := tm.Results()[0].TestCases[1].SummaryResult; !r.Pass || r.Timeout {
+		t.Fatalf("test was ended during client start: %s", spew.Sdump(r))
+	}
+}
+
+// This test checks that a parent test is not timed out while its subtests run.
+func TestTestTimeoutSubtest(t *testing.T) {
+	var (
+		mu           sync.Mutex
+		subtestDone  bool
+		earlyDeletes int
+	)
+	suite := Suite{Name: "timeout"}
+	suite.Add(TestSpec{
+		Name: "parent",
+		Run: func(t *T) {
+			t.StartClient("client-1")
+			t.Run(TestSpec{
+				Name: "subtest",
+				Run: func(t *T) {
+					for i := 0; i < 8; i++ {
+						time.Sleep(100 * time.Millisecond)
+						t.Progress("still running")
+					}
+					mu.Lock()
+					subtestDone = true
+					mu.Unlock()
+				},
+			})
+		},
+	})
+
+	tm, srv := newFakeAPIWithEnv(&fakes.BackendHooks{
+		DeleteContainer: func(containerID string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			if !subtestDone {
+				earlyDeletes++
+			}
+			return nil
+		},
+	}, libhive.SimEnv{TestTimeout: 300 * time.Millisecond})
+	defer srv.Close()
+
+	if err := RunSuite(NewAt(srv.URL), suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	results := tm.Results()[0].TestCases
+	for id, r := range results {
+		if !r.SummaryResult.Pass || r.SummaryResult.Timeout {
+			t.Errorf("test %d (%s) was ended: %s", id, r.Name, spew.Sdump(r.SummaryResult))
+		}
+	}
+	if earlyDeletes > 0 {
+		t.Error("parent client was stopped while subtest was running")
+	}
+}
+
+// This test checks that tests have no deadline when hive runs without a test timeout.
+func TestTestNoDeadline(t *testing.T) {
+	suite := Suite{Name: "no timeout"}
+	suite.Add(TestSpec{
+		Name: "test",
+		Run: func(t *T) {
+			t.Progress("running")
+			if deadline, ok := t.Deadline(); ok {
+				t.Fatal("test has deadline", deadline)
+			}
+		},
+	})
+
+	tm, srv := newFakeAPI(nil)
+	defer srv.Close()
+
+	if err := RunSuite(NewAt(srv.URL), suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	if r := tm.Results()[0].TestCases[1].SummaryResult; !r.Pass {
+		t.Fatalf("test failed: %s", spew.Sdump(r))
+	}
+}
+
+// This test checks that parallel tests run concurrently up to the parallelism limit,
+// and that parent tests wait for their parallel subtests.
+func TestParallel(t *testing.T) {
+	var (
+		mu          sync.Mutex
+		running     int
+		maxRunning  int
+		parallelRun = func(t *T) {
+			mu.Lock()
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
+			time.Sleep(100 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}
+	)
+	suite := Suite{Name: "parallel"}
+	for i := 0; i < 4; i++ {
+		suite.Add(TestSpec{Name: fmt.Sprintf("test %d", i), Parallel: true, Run: parallelRun})
+	}
+	suite.Add(TestSpec{
+		Name:     "parent",
+		Parallel: true,
+		Run: func(t *T) {
+			for i := 0; i < 2; i++ {
+				parent := t
+				t.Run(TestSpec{
+					Name:     fmt.Sprintf("subtest %d", i),
+					Parallel: true,
+					Run: func(t *T) {
+						parallelRun(t)
+						parent.Log("subtest done")
+					},
+				})
+			}
+		},
+	})
+
+	tm, srv := newFakeAPI(nil)
+	defer srv.Close()
+	sim := NewAt(srv.URL)
+	sim.SetParallelism(2)
+
+	if err := RunSuite(sim, suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	if maxRunning != 2 {
+		t.Errorf("wrong number of concurrent tests: %d", maxRunning)
+	}
+	results := tm.Results()[0].TestCases
+	if len(results) != 7 {
+		t.Fatalf("wrong number of test results: %d", len(results))
+	}
+	for _, tc := range results {
+		if tc.Name == "parent" && tc.SummaryResult.Details != "subtest done\nsubtest done\n" {
+			t.Errorf("parent test ended before subtests: %q", tc.SummaryResult.Details)
+		}
+	}
+}
+
+// This test checks that sequential tests of a suite wait for preceding parallel tests.
+func TestParallelSequential(t *testing.T) {
+	var (
+		mu         sync.Mutex
+		running    int
+		maxRunning int
+		run        = func(t *T) {
+			mu.Lock()
+			running++
+			maxRunning = max(maxRunning, running)
+			mu.Unlock()
+			time.Sleep(100 * time.Millisecond)
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}
+	)
+	suite := Suite{Name: "mixed"}
+	suite.Add(TestSpec{Name: "parallel 1", Parallel: true, Run: run})
+	suite.Add(TestSpec{Name: "parallel 2", Parallel: true, Run: run})
+	suite.Add(TestSpec{Name: "sequential", Run: run})
+
+	tm, srv := newFakeAPI(nil)
+	defer srv.Close()
+	defer tm.Terminate()
+	sim := NewAt(srv.URL)
+	sim.SetParallelism(1)
+
+	if err := RunSuite(sim, suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	if maxRunning != 1 {
+		t.Errorf("wrong number of concurrent tests: %d", maxRunning)
+	}
+}
+
+// This test checks that files in the test work directory are stored by the host.
+func TestWorkDir(t *testing.T) {
+	var localDir string
+	suite := Suite{Name: "workdir"}
+	suite.Add(TestSpec{
+		Name: "files",
+		Run: func(t *T) {
+			localDir = t.WorkDir()
+			os.WriteFile(filepath.Join(localDir, "a.txt"), []byte("aaa"), 0644)
+			os.MkdirAll(filepath.Join(localDir, "sub"), 0755)
+			os.WriteFile(filepath.Join(localDir, "sub", "b.txt"), []byte("bb"), 0644)
+		},
+	})
+	suite.Add(TestSpec{
+		Name: "no files",
+		Run:  func(t *T) {},
+	})
+
+	logdir := t.TempDir()
+	tm, srv := newFakeAPIWithEnv(nil, libhive.SimEnv{LogDir: logdir})
+	defer srv.Close()
+
+	if err := RunSuite(NewAt(srv.URL), suite); err != nil {
+		t.Fatal("suite run failed:", err)
+	}
+	tm.Terminate()
+
+	if _, err := os.Stat(localDir); !os.IsNotExist(err) {
+		t.Errorf("local work directory was not removed (err=%v)", err)
+	}
+	results := tm.Results()[0].TestCases
+	if r := results[1]; r.WorkDir == "" || !r.SummaryResult.Pass {
+		t.Fatalf("wrong result for test with files: %s", spew.Sdump(r))
+	}
+	dir := filepath.Join(logdir, filepath.FromSlash(results[1].WorkDir))
+	for name, want := range map[string]string{"a.txt": "aaa", "sub/b.txt": "bb"} {
+		content, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+		if err != nil || string(content) != want {
+			t.Errorf("wrong content of %s: %q (err=%v)", name, content, err)
+		}
+	}
+	if r := results[2]; r.WorkDir != "" {
+		t.Errorf("test without files has work directory %q", r.WorkDir)
+	}
+}
+
 // removeTimestamps removes test timestamps and runtime metadata in results so they can be
 // compared using reflect.DeepEqual.
 func removeTimestamps(result map[libhive.TestSuiteID]*libhive.TestSuite) {
@@ -0,0 +1,112 @@
+package hivesim
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ErrVersionMismatch is returned when a client version does not satisfy a constraint.
+var ErrVersionMismatch = errors.New("client version mismatch")
+
+// versionRE matches the first dotted version number in a client version string.
+// Client version strings come in many formats, e.g.
+//
+//	Geth/v1.10.0-unstable-8e547eec-20210224/linux-amd64/go1.16
+//	besu/v21.1.1-dev-f1c74ed2/linux-x86_64/oracle_openjdk-java-11
+var versionRE = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?`)
+
+// version is a semantic version number. Pre-release and build
+// suffixes are not tracked.
+type version [3]int
+
+func parseVersion(s string) (v version, err error) {
+	m := versionRE.FindStringSubmatch(s)
+	if m == nil {
+		return v, fmt.Errorf("no version number in %q", s)
+	}
+	for i := range v {
+		if m[i+1] == "" {
+			continue
+		}
+		if v[i], err = strconv.Atoi(m[i+1]); err != nil {
+			return v, fmt.Errorf("invalid version number in %q", s)
+		}
+	}
+	return v, nil
+}
+
+func (v version) cmp(other version) int {
+	for i := range v {
+		switch {
+		case v[i] < other[i]:
+			return -1
+		case v[i] > other[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionOps are the supported constraint operators. Two-character
+// operators must come first so they are matched before their prefixes.
+var versionOps = []string{">=", "<=", "!=", "==", ">", "<", "="}
+
+// checkVersion reports whether the version string satisfies the constraint.
+func checkVersion(versionString, constraint string) (bool, error) {
+	constraint = strings.TrimSpace(constraint)
+	op := "="
+	for _, o := range versionOps {
+		if strings.HasPrefix(constraint, o) {
+			op = o
+			constraint = strings.TrimSpace(constraint[len(o):])
+			break
+		}
+	}
+	want, err := parseVersion(constraint)
+	if err != nil {
+		return false, fmt.Errorf("invalid version constraint: %v", err)
+	}
+	have, err := parseVersion(versionString)
+	if err != nil {
+		return false, err
+	}
+	c := have.cmp(want)
+	switch op {
+	case ">=":
+		return c >= 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	case "<":
+		return c < 0, nil
+	case "!=":
+		return c != 0, nil
+	default:
+		return c == 0, nil
+	}
+}
+
+// CheckVersion checks that the client version satisfies all given constraints.
+// Constraints have the form "<op><version>", where op is one of =, ==, !=, <, <=, >, >=,
+// e.g. ">=1.14.0". If the operator is omitted, the version must match exactly.
+//
+// Versions are compared by major, minor and patch number only. The version number is
+// taken from the first dotted number in the client's version string.
+//
+// The returned error wraps ErrVersionMismatch if a constraint is not satisfied.
+func (m *ClientDefinition) CheckVersion(constraints ...string) error {
+	for _, c := range constraints {
+		ok, err := checkVersion(m.Version, c)
+		if err != nil {
+			return fmt.Errorf("client %s: %v", m.Name, err)
+		}
+		if !ok {
+			return fmt.Errorf("%w: %s version %q does not satisfy %q", ErrVersionMismatch, m.Name, m.Version, c)
+		}
+	}
+	return nil
+}
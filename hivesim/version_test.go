@@ -0,0 +1,74 @@
+package hivesim
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckVersion(t *testing.T) {
+	tests := []struct {
+		version    string
+		constraint string
+		want       bool
+	}{
+		{"Geth/v1.10.0-unstable-8e547eec-20210224/linux-amd64/go1.16", ">=1.10.0", true},
+		{"Geth/v1.10.0-unstable-8e547eec-20210224/linux-amd64/go1.16", ">1.10.0", false},
+		{"Geth/v1.10.0-unstable-8e547eec-20210224/linux-amd64/go1.16", "<1.11", true},
+		{"besu/v21.1.1-dev-f1c74ed2/linux-x86_64/oracle_openjdk-java-11", "21.1.1", true},
+		{"besu/v21.1.1-dev-f1c74ed2/linux-x86_64/oracle_openjdk-java-11", "!= 21.1.1", false},
+		{"1.25.4+2bf8cfd4", "<= 1.25.4", true},
+		{"1.25.4+2bf8cfd4", "== v1.25.3", false},
+	}
+	for _, test := range tests {
+		ok, err := checkVersion(test.version, test.constraint)
+		if err != nil {
+			t.Errorf("%q %q: unexpected error: %v", test.version, test.constraint, err)
+			continue
+		}
+		if ok != test.want {
+			t.Errorf("%q %q: got %t, want %t", test.version, test.constraint, ok, test.want)
+		}
+	}
+}
+
+func TestCheckVersionErrors(t *testing.T) {
+	if _, err := checkVersion("1.0.0", ">=latest"); err == nil {
+		t.Error("no error for invalid constraint")
+	}
+	if _, err := checkVersion("unknown", ">=1.0.0"); err == nil {
+		t.Error("no error for version string without number")
+	}
+}
+
+// This test checks client definition lookup with version constraints.
+func TestClientDefinition(t *testing.T) {
+	tm, srv := newFakeAPI(nil)
+	defer srv.Close()
+	defer tm.Terminate()
+
+	sim := NewAt(srv.URL)
+	def, err := sim.ClientDefinition("client-1")
+	if err != nil {
+		t.Fatal("can't get client definition:", err)
+	}
+	if def.Name != "client-1" {
+		t.Fatalf("wrong client definition returned: %q", def.Name)
+	}
+	if _, err := sim.ClientDefinition("client-3"); err == nil {
+		t.Fatal("no error for unknown client")
+	}
+	// The fake client versions contain no version number.
+	if def, err := sim.ClientDefinition("client-1", ">=1.0.0"); err == nil {
+		t.Fatal("no error for unparseable client version")
+	} else if def == nil || def.Name != "client-1" {
+		t.Fatal("client definition not returned with version error")
+	}
+
+	def.Version = "Geth/v1.14.3-stable/linux-amd64/go1.22"
+	if err := def.CheckVersion(">=1.14.0", "<1.15.0"); err != nil {
+		t.Fatal("unexpected error:", err)
+	}
+	if err := def.CheckVersion(">=1.15.0"); !errors.Is(err, ErrVersionMismatch) {
+		t.Fatalf("wrong error for unsatisfied constraint: %v", err)
+	}
+}
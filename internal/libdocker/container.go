@@ -103,6 +103,9 @@ func (b *ContainerBackend) CreateContainer(ctx context.Context, imageName string
 		},
 	}
 
+	if opt.CPUSet != "" {
+		createOpts.HostConfig = &docker.HostConfig{CPUSetCPUs: opt.CPUSet}
+	}
 	if opt.Input != nil {
 		// Pre-announce that stdin will be attached. The stdin attachment
 		// will fail silently if this is not set.
@@ -117,6 +120,9 @@ func (b *ContainerBackend) CreateContainer(ctx context.Context, imageName string
 	}
 
 	c, err := b.client.CreateContainer(createOpts)
+	if err == docker.ErrNoSuchImage {
+		return "", fmt.Errorf("%w: %s", libhive.ErrImageNotFound, imageName)
+	}
 	if err != nil {
 		return "", err
 	}
@@ -199,7 +205,7 @@ func (b *ContainerBackend) StartContainer(ctx context.Context, containerID strin
 	case <-containerExit:
 		checkErr = errors.New("terminated unexpectedly")
 	case <-ctx.Done():
-		checkErr = errors.New("timed out waiting for container startup")
+		checkErr = libhive.ErrContainerTimeout
 	}
 	if checkErr != nil {
 		b.DeleteContainer(containerID)
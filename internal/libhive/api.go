@@ -7,12 +7,16 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
 	"mime/multipart"
 	"net/http"
 	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/ethereum/hive/internal/simapi"
@@ -34,6 +38,7 @@ func newSimulationAPI(b ContainerBackend, env SimEnv, tm *TestManager, hive Hive
 	router := mux.NewRouter()
 	router.HandleFunc("/hive", api.getHiveInfo).Methods("GET")
 	router.HandleFunc("/clients", api.getClientTypes).Methods("GET")
+	router.HandleFunc("/parameters", api.getParameters).Methods("GET")
 	router.HandleFunc("/testsuite/{suite}/test/{test}/node/{node}/exec", api.execInClient).Methods("POST")
 	router.HandleFunc("/testsuite/{suite}/test/{test}/node/{node}", api.getNodeStatus).Methods("GET")
 	router.HandleFunc("/testsuite/{suite}/test/{test}/node", api.startClient).Methods("POST")
@@ -41,6 +46,9 @@ func newSimulationAPI(b ContainerBackend, env SimEnv, tm *TestManager, hive Hive
 	router.HandleFunc("/testsuite/{suite}/test/{test}/node/{node}/pause", api.pauseClient).Methods("POST")
 	router.HandleFunc("/testsuite/{suite}/test/{test}/node/{node}/pause", api.unpauseClient).Methods("DELETE")
 	router.HandleFunc("/testsuite/{suite}/test", api.startTest).Methods("POST")
+	router.HandleFunc("/testsuite/{suite}/test/{test}/progress", api.testProgress).Methods("POST")
+	router.HandleFunc("/testsuite/{suite}/test/{test}/progress", api.getTestDeadline).Methods("GET")
+	router.HandleFunc("/testsuite/{suite}/test/{test}/files", api.uploadTestFiles).Methods("POST")
 	// post because the delete http verb does not always support a message body
 	router.HandleFunc("/testsuite/{suite}/test/{test}", api.endTest).Methods("POST")
 	router.HandleFunc("/testsuite", api.startSuite).Methods("POST")
@@ -71,6 +79,15 @@ func (api *simAPI) getClientTypes(w http.ResponseWriter, r *http.Request) {
 	serveJSON(w, api.tm.clientDefs)
 }
 
+// getParameters returns the simulator parameters.
+func (api *simAPI) getParameters(w http.ResponseWriter, r *http.Request) {
+	params := api.env.SimParams
+	if params == nil {
+		params = make(map[string]string)
+	}
+	serveJSON(w, params)
+}
+
 // startSuite starts a suite.
 func (api *simAPI) startSuite(w http.ResponseWriter, r *http.Request) {
 	var suite simapi.TestRequest
@@ -125,7 +142,7 @@ func (api *simAPI) startTest(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	testID, err := api.tm.StartTest(suiteID, test.Name, test.Description)
+	testID, err := api.tm.StartTest(suiteID, TestID(test.Parent), test.Name, test.Description)
 	if err != nil {
 		err := fmt.Errorf("can't start test case: %s", err.Error())
 		serveError(w, err, http.StatusInternalServerError)
@@ -164,6 +181,87 @@ func (api *simAPI) endTest(w http.ResponseWriter, r *http.Request) {
 	serveOK(w)
 }
 
+// testProgress records progress of a test case, extending its timeout.
+func (api *simAPI) testProgress(w http.ResponseWriter, r *http.Request) {
+	suiteID, testID, err := api.requestSuiteAndTest(r)
+	if err != nil {
+		serveError(w, err, http.StatusBadRequest)
+		return
+	}
+	var progress simapi.TestProgress
+	if err := json.NewDecoder(r.Body).Decode(&progress); err != nil {
+		serveError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	deadline, err := api.tm.TestProgress(testID)
+	if err != nil {
+		serveError(w, err, http.StatusNotFound)
+		return
+	}
+	slog.Info("API: test progress", "suite", suiteID, "test", testID, "msg", progress.Message)
+	serveJSON(w, deadlineResponse(deadline))
+}
+
+// uploadTestFiles stores files in the work directory of a test case.
+func (api *simAPI) uploadTestFiles(w http.ResponseWriter, r *http.Request) {
+	suiteID, testID, err := api.requestSuiteAndTest(r)
+	if err != nil {
+		serveError(w, err, http.StatusBadRequest)
+		return
+	}
+	const maxMemory = 8 * 1024 * 1024
+	if err := r.ParseMultipartForm(maxMemory); err != nil {
+		slog.Error("API: could not parse files request", "error", err)
+		err := fmt.Errorf("could not parse files request")
+		serveError(w, err, http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	files := make(map[string]*multipart.FileHeader)
+	for key, fheaders := range r.MultipartForm.File {
+		if len(fheaders) > 0 {
+			files[key] = fheaders[0]
+		}
+	}
+	switch err := api.tm.StoreTestFiles(testID, files); {
+	case errors.Is(err, ErrNoSuchTestCase):
+		serveError(w, err, http.StatusNotFound)
+	case errors.Is(err, ErrInvalidFilePath):
+		serveError(w, err, http.StatusBadRequest)
+	case err != nil:
+		slog.Error("API: can't store test files", "suite", suiteID, "test", testID, "error", err)
+		serveErrorCode(w, err, http.StatusInternalServerError, errorCode(err, simapi.ErrCodeInternal))
+	default:
+		slog.Debug("API: test files stored", "suite", suiteID, "test", testID, "count", len(files))
+		serveOK(w)
+	}
+}
+
+// getTestDeadline returns the timeout deadline of a test case.
+func (api *simAPI) getTestDeadline(w http.ResponseWriter, r *http.Request) {
+	_, testID, err := api.requestSuiteAndTest(r)
+	if err != nil {
+		serveError(w, err, http.StatusBadRequest)
+		return
+	}
+	deadline, err := api.tm.TestDeadline(testID)
+	if err != nil {
+		serveError(w, err, http.StatusNotFound)
+		return
+	}
+	serveJSON(w, deadlineResponse(deadline))
+}
+
+func deadlineResponse(deadline time.Time) *simapi.TestProgressResponse {
+	var resp simapi.TestProgressResponse
+	if !deadline.IsZero() {
+		resp.Deadline = &deadline
+	}
+	return &resp
+}
+
 // startClient starts a client container.
 func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 	suiteID, testID, err := api.requestSuiteAndTest(r)
@@ -171,6 +269,8 @@ func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 		serveError(w, err, http.StatusBadRequest)
 		return
 	}
+	// Starting the client can take a while, don't time out the test meanwhile.
+	defer api.tm.beginTestCall(testID)()
 
 	// Client launch parameters are given as multipart/form-data.
 	const maxMemory = 8 * 1024 * 1024
@@ -200,7 +300,11 @@ func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 	clientDef, err := api.checkClient(&clientConfig)
 	if err != nil {
 		slog.Error("API: " + err.Error())
-		serveError(w, err, http.StatusBadRequest)
+		code := simapi.ErrCodeBadRequest
+		if err == errUnknownClient {
+			code = simapi.ErrCodeUnknownClient
+		}
+		serveErrorCode(w, err, http.StatusBadRequest, code)
 		return
 	}
 	// Get the network names, if any, for the container to be connected to at start.
@@ -210,6 +314,12 @@ func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 		serveError(w, err, http.StatusBadRequest)
 		return
 	}
+	if clientConfig.CPUSet != "" && !ValidCPUSet(clientConfig.CPUSet) {
+		err := fmt.Errorf("invalid cpuset %q", clientConfig.CPUSet)
+		slog.Error("API: "+err.Error(), "client", clientDef.Name)
+		serveError(w, err, http.StatusBadRequest)
+		return
+	}
 
 	files := make(map[string]*multipart.FileHeader)
 	for key, fheaders := range r.MultipartForm.File {
@@ -237,6 +347,12 @@ func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 	if env["HIVE_LOGLEVEL"] == "" {
 		env["HIVE_LOGLEVEL"] = strconv.Itoa(api.env.SimLogLevel)
 	}
+	// Set the JWT secret configured for the client, unless the simulator chose one.
+	if env["HIVE_JWTSECRET"] == "" && clientDef.JWTSecret != "" {
+		env["HIVE_JWTSECRET"] = clientDef.JWTSecret
+	}
+	// Only the names are logged here, values may contain credentials.
+	slog.Debug("API: client environment", "client", clientDef.Name, "vars", slices.Sorted(maps.Keys(env)))
 
 	// Set up the timeout.
 	timeout := api.env.ClientStartTimeout
@@ -258,12 +374,13 @@ func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 	containerName := GenerateClientContainerName(clientDef.Name, suiteID, testID)
 
 	// Create the client container.
-	options := ContainerOptions{Env: env, Files: files, Labels: labels, Name: containerName}
+	options := ContainerOptions{Env: env, Files: files, Labels: labels, Name: containerName, CPUSet: clientConfig.CPUSet}
 	containerID, err := api.backend.CreateContainer(ctx, clientDef.Image, options)
 	if err != nil {
 		slog.Error("API: client container create failed", "client", clientDef.Name, "error", err)
+		code := errorCode(err, simapi.ErrCodeClientCreateFailed)
 		err := fmt.Errorf("client container create failed (%v)", err)
-		serveError(w, err, http.StatusInternalServerError)
+		serveErrorCode(w, err, http.StatusInternalServerError, code)
 		return
 	}
 
@@ -314,18 +431,59 @@ func (api *simAPI) startClient(w http.ResponseWriter, r *http.Request) {
 
 		// Register the node. This should always be done, even if starting the container
 		// failed, to ensure that the failed client log is associated with the test.
-		api.tm.RegisterNode(testID, info.ID, clientInfo)
+		if regErr := api.tm.RegisterNode(testID, info.ID, clientInfo); regErr != nil {
+			// The test has ended while the client was starting. Nothing will stop
+			// the container, so remove it here.
+			slog.Error("API: could not register client", "client", clientDef.Name, "container", containerID[:8], "error", regErr)
+			api.backend.DeleteContainer(info.ID)
+			if info.Wait != nil {
+				info.Wait()
+			}
+			if err == nil {
+				serveError(w, regErr, http.StatusNotFound)
+				return
+			}
+		}
 	}
 	if err != nil {
 		slog.Error("API: could not start client", "client", clientDef.Name, "container", containerID[:8], "error", err)
+		code := errorCode(err, simapi.ErrCodeClientStartFailed)
 		err := fmt.Errorf("client did not start: %v", err)
-		serveError(w, err, http.StatusInternalServerError)
+		serveErrorCode(w, err, http.StatusInternalServerError, code)
 		return
 	}
 
 	// It's started.
 	slog.Info("API: client "+clientDef.Name+" started", "suite", suiteID, "test", testID, "container", containerID[:8])
-	serveJSON(w, &simapi.StartNodeResponse{ID: info.ID, IP: info.IP})
+	serveJSON(w, &simapi.StartNodeResponse{ID: info.ID, IP: info.IP, JWTSecret: env["HIVE_JWTSECRET"]})
+}
+
+// cpusetRE matches CPU lists in the format accepted by docker, e.g. "0-3,5".
+var cpusetRE = regexp.MustCompile(`^\d+(-\d+)?(,\d+(-\d+)?)*$`)
+
+// ValidCPUSet reports whether s is a valid CPU list for ContainerOptions.CPUSet.
+func ValidCPUSet(s string) bool {
+	return cpusetRE.MatchString(s)
+}
+
+// sensitiveEnvMarkers are substrings of environment variable names
+// whose values are redacted.
+var sensitiveEnvMarkers = []string{"SECRET", "TOKEN", "PASSWORD", "PRIVATEKEY", "CREDENTIAL"}
+
+// redactEnv returns a copy of env in which the values of sensitive variables
+// are replaced. Variables are sensitive if their name contains one of
+// sensitiveEnvMarkers.
+func redactEnv(env map[string]string) map[string]string {
+	redacted := make(map[string]string, len(env))
+	for k, v := range env {
+		if slices.ContainsFunc(sensitiveEnvMarkers, func(m string) bool {
+			return strings.Contains(strings.ToUpper(k), m)
+		}) {
+			v = "<redacted>"
+		}
+		redacted[k] = v
+	}
+	return redacted
 }
 
 // clientLogFilePaths determines the log file path of a client container.
@@ -339,16 +497,25 @@ func (api *simAPI) clientLogFilePaths(clientName, containerID string) (jsonPath
 	return jsonPath, file
 }
 
+var (
+	errMissingClient = errors.New("missing client type in start request")
+	errUnknownClient = errors.New("unknown client type in start request")
+)
+
 func (api *simAPI) checkClient(req *simapi.NodeConfig) (*ClientDefinition, error) {
 	if req.Client == "" {
-		return nil, errors.New("missing client type in start request")
+		return nil, errMissingClient
 	}
 	for _, client := range api.tm.clientDefs {
-		if client.Name == req.Client {
+		if req.Nametag != "" {
+			if client.Client == req.Client && client.Nametag == req.Nametag {
+				return client, nil
+			}
+		} else if client.Name == req.Client {
 			return client, nil
 		}
 	}
-	return nil, errors.New("unknown client type in start request")
+	return nil, errUnknownClient
 }
 
 // checkClientNetworks pre-checks the existence of initial networks for a client container.
@@ -446,6 +613,7 @@ func (api *simAPI) execInClient(w http.ResponseWriter, r *http.Request) {
 		serveError(w, err, http.StatusBadRequest)
 		return
 	}
+	defer api.tm.beginTestCall(testID)()
 
 	node := mux.Vars(r)["node"]
 	nodeInfo, err := api.tm.GetNodeInfo(suiteID, testID, node)
@@ -644,9 +812,39 @@ func serveOK(w http.ResponseWriter) {
 	io.WriteString(w, "null")
 }
 
+// serveError responds with an error. The error code is derived from the HTTP status.
 func serveError(w http.ResponseWriter, err error, status int) {
-	resp, _ := json.Marshal(&simapi.Error{Error: err.Error()})
+	var code string
+	switch {
+	case status == http.StatusNotFound:
+		code = simapi.ErrCodeNotFound
+	case status >= 500:
+		code = simapi.ErrCodeInternal
+	default:
+		code = simapi.ErrCodeBadRequest
+	}
+	serveErrorCode(w, err, status, code)
+}
+
+func serveErrorCode(w http.ResponseWriter, err error, status int, code string) {
+	resp, _ := json.Marshal(&simapi.Error{Error: err.Error(), Code: code})
 	w.Header().Set("content-type", "application/json")
 	w.WriteHeader(status)
 	w.Write(resp)
 }
+
+// errorCode classifies container backend errors. If the error is not of a known kind,
+// the fallback code is returned.
+func errorCode(err error, fallback string) string {
+	switch {
+	case errors.Is(err, ErrImageNotFound):
+		return simapi.ErrCodeImageMissing
+	case errors.Is(err, ErrContainerTimeout):
+		return simapi.ErrCodeClientStartTimeout
+	case errors.Is(err, syscall.ENOSPC) || strings.Contains(err.Error(), "no space left on device"):
+		// Docker daemon errors arrive as plain text, so the message is checked as well.
+		return simapi.ErrCodeOutOfDisk
+	default:
+		return fallback
+	}
+}
@@ -0,0 +1,41 @@
+package libhive
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactEnv(t *testing.T) {
+	env := map[string]string{
+		"HIVE_LOGLEVEL":          "3",
+		"HIVE_JWTSECRET":         "0x7365637265747365637265747365637265747365637265747365637265747365",
+		"HIVE_MIRROR_TOKEN":      "abc",
+		"HIVE_CLIQUE_PRIVATEKEY": "9c647b8b7c4e7c3490668fb6c11473619db80c93704c70893d3813af4090c39c",
+	}
+	got := redactEnv(env)
+	want := map[string]string{
+		"HIVE_LOGLEVEL":          "3",
+		"HIVE_JWTSECRET":         "<redacted>",
+		"HIVE_MIRROR_TOKEN":      "<redacted>",
+		"HIVE_CLIQUE_PRIVATEKEY": "<redacted>",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("wrong redacted env: %v", got)
+	}
+	if env["HIVE_MIRROR_TOKEN"] != "abc" {
+		t.Fatal("input env was modified")
+	}
+}
+
+func TestValidCPUSet(t *testing.T) {
+	for _, s := range []string{"0", "0-3", "1,3", "0-3,5,7-9"} {
+		if !ValidCPUSet(s) {
+			t.Errorf("%q should be valid", s)
+		}
+	}
+	for _, s := range []string{"", "a", "0-", "1,,3", "0-3 ", "-1"} {
+		if ValidCPUSet(s) {
+			t.Errorf("%q should be invalid", s)
+		}
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"strconv"
 	"sync/atomic"
 	"time"
+
+	"github.com/ethereum/hive/internal/simapi"
 )
 
 // Docker label keys used by Hive
@@ -146,6 +148,16 @@ type TestCase struct {
 	End           time.Time              `json:"end"`
 	SummaryResult TestResult             `json:"summaryResult"` // The result of the whole test case.
 	ClientInfo    map[string]*ClientInfo `json:"clientInfo"`    // Info about each client.
+
+	// Directory of files stored by the test, relative to the log directory.
+	WorkDir string `json:"workDir,omitempty"`
+
+	// Inactivity timeout, only set when SimEnv.TestTimeout is configured.
+	// The timer is paused while API calls or subtests of the test are in progress.
+	timer       *time.Timer
+	deadline    time.Time
+	activeCalls int
+	parent      *TestCase
 }
 
 // TestResult represents the result of a test case.
@@ -157,6 +169,9 @@ type TestResult struct {
 	// suite's TestDetailsLog file ("log").
 	Details    string          `json:"details,omitempty"`
 	LogOffsets *TestLogOffsets `json:"log,omitempty"`
+
+	// Measurements are numeric values reported by the test, e.g. benchmark results.
+	Measurements []simapi.Measurement `json:"measurements,omitempty"`
 }
 
 type TestLogOffsets struct {
@@ -189,6 +204,16 @@ type ClientDefinition struct {
 	Version string         `json:"version"`
 	Image   string         `json:"-"` // not exposed via API
 	Meta    ClientMetadata `json:"meta"`
+
+	// Base client name and nametag of the build.
+	Client  string `json:"client,omitempty"`
+	Nametag string `json:"nametag,omitempty"`
+
+	// Engine API JWT secret from the client file.
+	JWTSecret string `json:"jwtSecret,omitempty"`
+
+	// Default parameters from the client file.
+	Params map[string]string `json:"params,omitempty"`
 }
 
 // ExecInfo is the result of running a script in a client container.
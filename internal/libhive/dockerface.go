@@ -2,6 +2,7 @@ package libhive
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -53,6 +54,12 @@ type APIServer interface {
 // This error is returned by NetworkNameToID if a docker network is not present.
 var ErrNetworkNotFound = fmt.Errorf("network not found")
 
+// These errors are returned by CreateContainer and StartContainer.
+var (
+	ErrImageNotFound    = errors.New("image not found")
+	ErrContainerTimeout = errors.New("timed out waiting for container startup")
+)
+
 // ContainerOptions contains the launch parameters for docker containers.
 type ContainerOptions struct {
 	Env   map[string]string
@@ -75,6 +82,9 @@ type ContainerOptions struct {
 
 	// Name: Docker container name (optional)
 	Name string
+
+	// CPUSet: CPUs in which the container may run, e.g. "0-3" or "1,3" (optional)
+	CPUSet string
 }
 
 // ContainerInfo is returned by StartContainer.
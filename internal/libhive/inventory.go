@@ -1,6 +1,7 @@
 package libhive
 
 import (
+	"encoding/hex"
 	"fmt"
 	"io"
 	"log/slog"
@@ -196,6 +197,14 @@ type ClientDesignator struct {
 
 	// Arguments passed to the docker build.
 	BuildArgs map[string]string `yaml:"build_args,omitempty" json:"build_args,omitempty"`
+
+	// Default client parameters. These are provided to simulators in the client
+	// definition. All names must start with HIVE_.
+	Params map[string]string `yaml:"params,omitempty" json:"params,omitempty"`
+
+	// Engine API JWT secret of the client, as 32 hex-encoded bytes. If set, it is
+	// passed to the client container in HIVE_JWTSECRET. This is not stored in results.
+	JWTSecret string `yaml:"jwt_secret,omitempty" json:"-"`
 }
 
 func (c ClientDesignator) buildString() string {
@@ -316,7 +325,7 @@ func validateClients(inv *Inventory, list []ClientDesignator) error {
 	occurrences := make(map[string]int)
 	clientTags := make(map[string]set[string])
 
-	for _, c := range list {
+	for i, c := range list {
 		occurrences[c.Client]++
 
 		// Validate client exists.
@@ -336,6 +345,21 @@ func validateClients(inv *Inventory, list []ClientDesignator) error {
 				slog.Warn(fmt.Sprintf("unknown build arg %q in clients.yaml file", key))
 			}
 		}
+		// Check JWT secret. It is stored in the format expected by
+		// client scripts, i.e. lowercase hex with 0x prefix.
+		if c.JWTSecret != "" {
+			secret, err := hex.DecodeString(strings.TrimPrefix(c.JWTSecret, "0x"))
+			if err != nil || len(secret) != 32 {
+				return fmt.Errorf("client %s: jwt_secret must be 32 hex-encoded bytes", c.Client)
+			}
+			list[i].JWTSecret = "0x" + hex.EncodeToString(secret)
+		}
+		// Check parameters.
+		for key := range c.Params {
+			if !strings.HasPrefix(key, "HIVE_") {
+				return fmt.Errorf("client %s: parameter %q does not start with HIVE_", c.Client, key)
+			}
+		}
 		clientTags[c.Client] = clientTags[c.Client].add(c.BuildArgs["tag"])
 	}
 
@@ -145,13 +145,15 @@ func TestParseClientListYAML(t *testing.T) {
     github: org/repository
 - client: supereth3000
   nametag: thebest
+  params:
+    HIVE_CACHE_SIZE: "4096"
 `
 
 	expectedOutput := []ClientDesignator{
 		{Client: "go-ethereum", Nametag: "custom", DockerfileExt: "git", BuildArgs: map[string]string{"tag": "custom"}},
 		{Client: "go-ethereum", DockerfileExt: "local"},
 		{Client: "supereth3000", Nametag: "github_org/repository", BuildArgs: map[string]string{"github": "org/repository"}},
-		{Client: "supereth3000", Nametag: "thebest"},
+		{Client: "supereth3000", Nametag: "thebest", Params: map[string]string{"HIVE_CACHE_SIZE": "4096"}},
 	}
 
 	var inv Inventory
@@ -172,6 +174,48 @@ func TestParseClientListYAML(t *testing.T) {
 	}
 }
 
+func TestParseClientListYAMLInvalidParams(t *testing.T) {
+	yamlInput := `
+- client: go-ethereum
+  params:
+    CACHE_SIZE: "4096"
+`
+	var inv Inventory
+	inv.AddClient("go-ethereum", nil)
+
+	_, err := ParseClientListYAML(&inv, strings.NewReader(yamlInput))
+	if err == nil || !strings.Contains(err.Error(), "CACHE_SIZE") {
+		t.Fatalf("expected error for parameter without HIVE_ prefix, got %v", err)
+	}
+}
+
+func TestParseClientListYAMLJWTSecret(t *testing.T) {
+	var inv Inventory
+	inv.AddClient("go-ethereum", nil)
+
+	// The secret is normalized to lowercase hex with 0x prefix.
+	yamlInput := `
+- client: go-ethereum
+  jwt_secret: "7365637265747365637265747365637265747365637265747365637265747ABC"
+`
+	res, err := ParseClientListYAML(&inv, strings.NewReader(yamlInput))
+	if err != nil {
+		t.Fatal("parse error:", err)
+	}
+	if res[0].JWTSecret != "0x7365637265747365637265747365637265747365637265747365637265747abc" {
+		t.Fatalf("wrong jwt_secret: %q", res[0].JWTSecret)
+	}
+
+	yamlInput = `
+- client: go-ethereum
+  jwt_secret: "0x1234"
+`
+	_, err = ParseClientListYAML(&inv, strings.NewReader(yamlInput))
+	if err == nil || !strings.Contains(err.Error(), "jwt_secret") {
+		t.Fatalf("expected error for invalid jwt_secret, got %v", err)
+	}
+}
+
 // This test ensures the real hive client definitions can be loaded.
 func TestLoadInventory(t *testing.T) {
 	basedir := filepath.FromSlash("../..")
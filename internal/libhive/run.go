@@ -72,10 +72,14 @@ func (r *Runner) buildClients(ctx context.Context, clientList []ClientDesignator
 			slog.Warn("can't read version info of "+client.Client, "image", image, "err", err)
 		}
 		r.clientDefs = append(r.clientDefs, &ClientDefinition{
-			Name:    client.Name(),
-			Version: strings.TrimSpace(string(version)),
-			Image:   image,
-			Meta:    r.inv.Clients[client.Client].Meta,
+			Name:      client.Name(),
+			Version:   strings.TrimSpace(string(version)),
+			Image:     image,
+			Meta:      r.inv.Clients[client.Client].Meta,
+			Params:    client.Params,
+			Client:    client.Client,
+			Nametag:   client.Nametag,
+			JWTSecret: client.JWTSecret,
 		})
 	}
 	if !anyBuilt {
@@ -219,6 +223,7 @@ func (r *Runner) run(ctx context.Context, sim string, env SimEnv, hiveInfo HiveI
 		},
 		Labels: simLabels,
 		Name:   containerName,
+		CPUSet: env.SimCPUSet,
 	}
 	containerID, err := r.container.CreateContainer(ctx, r.simImages[sim], opts)
 	if err != nil {
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"mime/multipart"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -24,6 +26,8 @@ var (
 	ErrNoSummaryResult          = errors.New("test case must be ended with a summary result")
 	ErrDBUpdateFailed           = errors.New("could not update results set")
 	ErrTestSuiteLimited         = errors.New("testsuite test count is limited")
+	ErrNoLogDir                 = errors.New("log directory is not configured")
+	ErrInvalidFilePath          = errors.New("invalid file path")
 )
 
 // SimEnv contains the simulation parameters.
@@ -37,10 +41,21 @@ type SimEnv struct {
 	SimTestPattern string
 	SimBuildArgs   []string
 
+	// CPUs in which the simulator container may run.
+	SimCPUSet string
+
+	// Simulator parameters, served by the /parameters API endpoint.
+	SimParams map[string]string
+
 	// This is the time limit for the simulation run.
 	// There is no default limit.
 	SimDurationLimit time.Duration
 
+	// This is the inactivity time limit for test cases. A test case that neither
+	// ends nor reports progress within this time is ended with a timeout result.
+	// There is no default limit.
+	TestTimeout time.Duration
+
 	// These are the clients which are made available to the simulator.
 	// If unset (i.e. nil), all built clients are used.
 	ClientList []ClientDesignator
@@ -105,6 +120,9 @@ func filterClientDesignators(clients []ClientDesignator) []ClientDesignator {
 			DockerfileExt: client.DockerfileExt,
 			BuildArgs:     make(map[string]string),
 		}
+		if len(client.Params) > 0 {
+			filteredClient.Params = redactEnv(client.Params)
+		}
 		
 		// Filter build args
 		for key, value := range client.BuildArgs {
@@ -478,8 +496,10 @@ func (manager *TestManager) StartTestSuite(name string, description string) (Tes
 	return newSuiteID, nil
 }
 
-// StartTest starts a new test case, returning the testcase id as a context identifier
-func (manager *TestManager) StartTest(testSuiteID TestSuiteID, name string, description string) (TestID, error) {
+// StartTest starts a new test case, returning the testcase id as a context identifier.
+// If parent is the ID of a running test case, the new test case is its subtest, and the
+// inactivity timeout of the parent is paused until the subtest ends.
+func (manager *TestManager) StartTest(testSuiteID TestSuiteID, parent TestID, name string, description string) (TestID, error) {
 	manager.testCaseMutex.Lock()
 	defer manager.testCaseMutex.Unlock()
 
@@ -497,6 +517,16 @@ func (manager *TestManager) StartTest(testSuiteID TestSuiteID, name string, desc
 		Description: description,
 		Start:       time.Now(),
 	}
+	if timeout := manager.config.TestTimeout; timeout > 0 {
+		newTestCase.deadline = newTestCase.Start.Add(timeout)
+		newTestCase.timer = time.AfterFunc(timeout, func() {
+			manager.timeoutTest(testSuiteID, newCaseID)
+		})
+	}
+	if parentCase, ok := testSuite.TestCases[parent]; ok && parentCase.End.IsZero() {
+		newTestCase.parent = parentCase
+		manager.pauseTimer(parentCase)
+	}
 	// add the test case to the test suite
 	testSuite.TestCases[newCaseID] = newTestCase
 	// and to the general map of id:testcases
@@ -510,6 +540,11 @@ func (manager *TestManager) EndTest(suiteID TestSuiteID, testID TestID, result *
 	manager.testCaseMutex.Lock()
 	defer manager.testCaseMutex.Unlock()
 
+	return manager.endTest(suiteID, testID, result)
+}
+
+// endTest finishes the test case. It must be called with testCaseMutex held.
+func (manager *TestManager) endTest(suiteID TestSuiteID, testID TestID, result *TestResult) error {
 	// Check if the test case is running
 	testSuite, ok := manager.runningTestSuites[suiteID]
 	if !ok {
@@ -526,12 +561,19 @@ func (manager *TestManager) EndTest(suiteID TestSuiteID, testID TestID, result *
 
 	// Add the results to the test case
 	testCase.End = time.Now()
+	if testCase.timer != nil {
+		testCase.timer.Stop()
+	}
 	if result.Details != "" && testSuite.testDetailsFile != nil {
 		offsets := manager.writeTestDetails(testSuite, testCase, result.Details)
 		result.Details = ""
 		result.LogOffsets = offsets
 	}
 	testCase.SummaryResult = *result
+	if testCase.parent != nil {
+		manager.resumeTimer(testCase.parent)
+		testCase.parent = nil
+	}
 
 	// Stop running clients.
 	for _, v := range testCase.ClientInfo {
@@ -547,6 +589,155 @@ func (manager *TestManager) EndTest(suiteID TestSuiteID, testID TestID, result *
 	return nil
 }
 
+// StoreTestFiles writes files of a running test case into its work directory. The
+// directory is created below the log directory on first use. Keys of the files map
+// are slash-separated paths relative to the work directory.
+func (manager *TestManager) StoreTestFiles(testID TestID, files map[string]*multipart.FileHeader) error {
+	if manager.config.LogDir == "" {
+		return ErrNoLogDir
+	}
+	for name := range files {
+		if !filepath.IsLocal(filepath.FromSlash(name)) {
+			return fmt.Errorf("%w: %q", ErrInvalidFilePath, name)
+		}
+	}
+
+	manager.testCaseMutex.Lock()
+	testCase, ok := manager.runningTestCases[testID]
+	if !ok {
+		manager.testCaseMutex.Unlock()
+		return ErrNoSuchTestCase
+	}
+	if testCase.WorkDir == "" {
+		testCase.WorkDir = fmt.Sprintf("files/%d-%s-%d", time.Now().Unix(), manager.simContainerID, testID)
+	}
+	dir := filepath.Join(manager.config.LogDir, filepath.FromSlash(testCase.WorkDir))
+	manager.testCaseMutex.Unlock()
+
+	for name, fh := range files {
+		if err := storeFile(filepath.Join(dir, filepath.FromSlash(name)), fh); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func storeFile(path string, fh *multipart.FileHeader) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	src, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		return err
+	}
+	return dst.Close()
+}
+
+// timeoutTest ends a test case which has exceeded the inactivity timeout.
+func (manager *TestManager) timeoutTest(suiteID TestSuiteID, testID TestID) {
+	manager.testCaseMutex.Lock()
+	defer manager.testCaseMutex.Unlock()
+
+	// The timer may fire just before an API call pauses it, or before progress is
+	// reported. The timer can't be stopped in this case, so check again here.
+	if testCase, ok := manager.runningTestCases[testID]; ok {
+		if testCase.activeCalls > 0 || time.Now().Before(testCase.deadline) {
+			return
+		}
+	}
+	result := &TestResult{
+		Pass:    false,
+		Timeout: true,
+		Details: fmt.Sprintf("Test was terminated by host (no progress reported within %v)", manager.config.TestTimeout),
+	}
+	if err := manager.endTest(suiteID, testID, result); err == nil {
+		slog.Warn("test timed out", "suite", suiteID, "test", testID)
+	}
+}
+
+// beginTestCall pauses the inactivity timeout of a test case while an API call of the
+// test, such as starting a client, is in progress. The returned function must be called
+// when the call is done. It restarts the timeout.
+func (manager *TestManager) beginTestCall(testID TestID) (done func()) {
+	manager.testCaseMutex.Lock()
+	defer manager.testCaseMutex.Unlock()
+
+	testCase, ok := manager.runningTestCases[testID]
+	if !ok {
+		return func() {}
+	}
+	manager.pauseTimer(testCase)
+	return func() {
+		manager.testCaseMutex.Lock()
+		defer manager.testCaseMutex.Unlock()
+		manager.resumeTimer(testCase)
+	}
+}
+
+// pauseTimer stops the inactivity timeout of a test case until resumeTimer is called.
+// It must be called with testCaseMutex held.
+func (manager *TestManager) pauseTimer(testCase *TestCase) {
+	if testCase.timer == nil {
+		return
+	}
+	testCase.activeCalls++
+	testCase.timer.Stop()
+}
+
+// resumeTimer restarts the inactivity timeout of a test case when it is no longer paused.
+// It must be called with testCaseMutex held.
+func (manager *TestManager) resumeTimer(testCase *TestCase) {
+	if testCase.timer == nil {
+		return
+	}
+	testCase.activeCalls--
+	if testCase.activeCalls == 0 && testCase.End.IsZero() {
+		testCase.timer.Reset(manager.config.TestTimeout)
+		testCase.deadline = time.Now().Add(manager.config.TestTimeout)
+	}
+}
+
+// TestProgress records progress of a running test case, extending its inactivity
+// deadline. It returns the new deadline, or the zero time if there is no timeout.
+func (manager *TestManager) TestProgress(testID TestID) (time.Time, error) {
+	manager.testCaseMutex.Lock()
+	defer manager.testCaseMutex.Unlock()
+
+	testCase, ok := manager.runningTestCases[testID]
+	if !ok {
+		return time.Time{}, ErrNoSuchTestCase
+	}
+	if testCase.timer != nil {
+		if testCase.activeCalls == 0 {
+			testCase.timer.Reset(manager.config.TestTimeout)
+		}
+		testCase.deadline = time.Now().Add(manager.config.TestTimeout)
+	}
+	return testCase.deadline, nil
+}
+
+// TestDeadline returns the inactivity deadline of a running test case, or the
+// zero time if there is no timeout.
+func (manager *TestManager) TestDeadline(testID TestID) (time.Time, error) {
+	manager.testCaseMutex.RLock()
+	defer manager.testCaseMutex.RUnlock()
+
+	testCase, ok := manager.runningTestCases[testID]
+	if !ok {
+		return time.Time{}, ErrNoSuchTestCase
+	}
+	return testCase.deadline, nil
+}
+
 func (manager *TestManager) writeTestDetails(suite *TestSuite, testCase *TestCase, text string) *TestLogOffsets {
 	var (
 		begin   = suite.testLogOffset
@@ -0,0 +1,38 @@
+package libhive
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// This test checks that a test which reported progress is not ended by a timer
+// callback that fired before the progress report.
+func TestTimeoutAfterProgress(t *testing.T) {
+	tm := NewTestManager(SimEnv{TestTimeout: time.Hour}, nil, nil, HiveInfo{})
+	suiteID, err := tm.StartTestSuite("suite", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	testID, err := tm.StartTest(suiteID, 0, "test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tm.TestProgress(testID); err != nil {
+		t.Fatal(err)
+	}
+	// Simulate the timer callback running late.
+	tm.timeoutTest(suiteID, testID)
+	if _, err := tm.TestDeadline(testID); err != nil {
+		t.Fatal("test was ended after reporting progress")
+	}
+
+	// When the deadline has passed, the test is ended.
+	tm.testCaseMutex.Lock()
+	tm.runningTestCases[testID].deadline = time.Now()
+	tm.testCaseMutex.Unlock()
+	tm.timeoutTest(suiteID, testID)
+	if _, err := tm.TestDeadline(testID); !errors.Is(err, ErrNoSuchTestCase) {
+		t.Fatal("test was not ended after deadline")
+	}
+}
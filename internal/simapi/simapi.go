@@ -1,12 +1,29 @@
 // Package simapi contains definitions of JSON objects used in the simulation API.
 package simapi
 
+import "time"
+
 type TestRequest struct {
 	Name        string `json:"name"`
 	DisplayName string `json:"display_name"`
 	Location    string `json:"location"`
 	Category    string `json:"category"`
 	Description string `json:"description"`
+
+	// Parent is the ID of the test which runs this test as a subtest.
+	Parent uint32 `json:"parent,omitempty"`
+}
+
+// TestProgress is sent by the simulator to report progress of a running test.
+type TestProgress struct {
+	Message string `json:"message"`
+}
+
+// TestProgressResponse is returned by the test progress endpoint.
+type TestProgressResponse struct {
+	// Deadline is the time at which the test will be ended by the host if no
+	// further progress is reported. It is nil when tests have no timeout.
+	Deadline *time.Time `json:"deadline,omitempty"`
 }
 
 // NodeConfig contains the launch parameters for a client container.
@@ -14,12 +31,22 @@ type NodeConfig struct {
 	Client      string            `json:"client"`
 	Networks    []string          `json:"networks"`
 	Environment map[string]string `json:"environment"`
+
+	// CPUSet restricts the client to the given CPUs, e.g. "0-3" or "1,3".
+	CPUSet string `json:"cpuset,omitempty"`
+
+	// Nametag selects a build of the client. If set, Client is the base client name.
+	Nametag string `json:"nametag,omitempty"`
 }
 
 // StartNodeResponse is returned by the client startup endpoint.
 type StartNodeResponse struct {
 	ID string `json:"id"` // Container ID.
 	IP string `json:"ip"` // IP address in bridge network
+
+	// JWTSecret is the engine API JWT secret of the client. It is empty
+	// if the client uses the default secret.
+	JWTSecret string `json:"jwtSecret,omitempty"`
 }
 
 // NodeResponse is the description of a running client as returned by the API.
@@ -28,10 +55,33 @@ type NodeResponse struct {
 	Name string `json:"name"`
 }
 
+// Measurement is a named numeric value reported with a test result, e.g. the
+// throughput achieved by a benchmark.
+type Measurement struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+	Unit  string  `json:"unit,omitempty"`
+}
+
 type ExecRequest struct {
 	Command []string `json:"command"`
 }
 
+// Error is the body of API error responses.
 type Error struct {
 	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
 }
+
+// These are the values of Error.Code.
+const (
+	ErrCodeBadRequest         = "bad_request"          // invalid request parameters
+	ErrCodeNotFound           = "not_found"            // the requested node/test/suite does not exist
+	ErrCodeInternal           = "internal"             // unclassified host-side failure
+	ErrCodeUnknownClient      = "unknown_client"       // client type is not available in this run
+	ErrCodeImageMissing       = "image_missing"        // client image does not exist on the host
+	ErrCodeClientCreateFailed = "client_create_failed" // client container could not be created
+	ErrCodeClientStartFailed  = "client_start_failed"  // client container exited or failed to start
+	ErrCodeClientStartTimeout = "client_start_timeout" // client did not open its port in time
+	ErrCodeOutOfDisk          = "out_of_disk"          // the host ran out of disk space
+)
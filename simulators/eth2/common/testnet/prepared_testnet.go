@@ -127,7 +127,9 @@ func PrepareTestnet(
 		"HIVE_LOGLEVEL": os.Getenv("HIVE_LOGLEVEL"),
 		"HIVE_NODETYPE": "full",
 	}
-	jwtSecret := hivesim.Params{"HIVE_JWTSECRET": "true"}
+	// Execution and beacon nodes must use the same JWT secret. Set the default secret
+	// for both, overriding any jwt_secret configured in the client file.
+	jwtSecret := hivesim.Params{"HIVE_JWTSECRET": fmt.Sprintf("%#x", JWT_SECRET)}
 	executionOpts := hivesim.Bundle(
 		eth1ConfigOpt,
 		eth1Bundle,
@@ -283,6 +285,7 @@ func PrepareTestnet(
 		stateOpt,
 		consensusConfigOpts,
 		optimisticSync,
+		jwtSecret,
 	)
 
 	validatorOpts := hivesim.Bundle(